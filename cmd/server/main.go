@@ -2,50 +2,134 @@ package main
 
 import (
 	"context"
-	"log"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/lypolix/avito_test/internal/availability"
 	"github.com/lypolix/avito_test/internal/config"
 	"github.com/lypolix/avito_test/internal/database"
+	"github.com/lypolix/avito_test/internal/escalation"
 	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/logging"
+	"github.com/lypolix/avito_test/internal/outbox"
 	"github.com/lypolix/avito_test/internal/repository"
+	"github.com/lypolix/avito_test/internal/repository/memory"
 	"github.com/lypolix/avito_test/internal/server"
 	"github.com/lypolix/avito_test/internal/services"
+	"github.com/lypolix/avito_test/internal/webhooks"
 )
 
 func main() {
+	slog.SetDefault(logging.New(os.Stderr, slog.LevelInfo))
+
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("failed to load config", err)
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.App.LogLevel))
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)), "component", "gin")
 	}
 
-	db, err := database.ConnectWithRetry(cfg.Database)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg.Database, os.Args[2:]); err != nil {
+			fatal("migrate failed", err)
+		}
+		return
+	}
+
+	repo, closeStore, err := openStore(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("failed to open storage", err)
 	}
-	defer db.Close()
-	log.Println("Connected to database")
+	defer closeStore()
 
-	repo := repository.NewRepository(db)
 	service := services.NewService(repo)
-	handler := handlers.NewHandler(service)
+	handler := handlers.NewHandler(service, cfg.App)
 
 	server := server.New(cfg.Server)
 	server.SetupRoutes(func(router *gin.Engine) {
 		handler.SetupRoutesWithRouter(router)
 	})
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go outbox.NewDispatcher(repo, cfg.Outbox, outboxSinks(repo, cfg.Outbox)...).Run(workerCtx)
+	go webhooks.NewWorker(repo, cfg.Webhooks).Run(workerCtx)
+	if cfg.Absences.AutoReassign {
+		go availability.NewScheduler(service, cfg.Absences).Run(workerCtx)
+	}
+	if cfg.Stale.Enabled {
+		go escalation.NewWorker(service, cfg.Stale).Run(workerCtx)
+	}
+
 	startServerWithShutdown(server, cfg)
 }
 
+// openStore builds the storage backend selected by cfg.Driver.
+func openStore(cfg config.DatabaseConfig) (repository.Store, func(), error) {
+	switch cfg.Driver {
+	case config.DriverMemory:
+		slog.Warn("using in-memory storage, data is lost on restart")
+		return memory.New(), func() {}, nil
+	case config.DriverSQLite:
+		db, err := database.OpenSQLite(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repository.NewSQLiteRepository(db), func() { db.Close() }, nil
+	}
+
+	db, err := database.ConnectWithRetry(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("connected to database", "host", cfg.Host, "name", cfg.Name)
+
+	if err := prepareSchema(context.Background(), db, cfg); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return repository.NewRepository(db), func() { db.Close() }, nil
+}
+
+// prepareSchema applies pending migrations when DB_AUTO_MIGRATE is set and refuses to
+// serve a schema older than the server.
+func prepareSchema(ctx context.Context, db *sql.DB, cfg config.DatabaseConfig) error {
+	migrator := database.NewPostgresMigrator(db)
+	if cfg.AutoMigrate {
+		if err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+	return migrator.CheckSchema(ctx)
+}
+
+func outboxSinks(repo repository.Store, cfg config.OutboxConfig) []outbox.Sink {
+	sinks := []outbox.Sink{webhooks.NewSink(repo)}
+	if cfg.LogSink {
+		sinks = append(sinks, outbox.LogSink{})
+	}
+	if cfg.HTTPSinkURL != "" {
+		sinks = append(sinks, outbox.NewHTTPSink(cfg.HTTPSinkURL, cfg.HTTPSinkTimeout))
+	}
+	return sinks
+}
+
 func startServerWithShutdown(server *server.Server, cfg *config.Config) {
 	go func() {
-		log.Printf("Starting server on :%s", cfg.Server.Port)
-		if err := server.Start(); err != nil {
-			log.Printf("Server error: %v", err)
+		slog.Info("starting server", "port", cfg.Server.Port)
+		if err := server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("server error", "error", err)
 		}
 	}()
 
@@ -53,14 +137,20 @@ func startServerWithShutdown(server *server.Server, cfg *config.Config) {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		fatal("server forced to shutdown", err)
 	}
 
-	log.Println("Server exited")
+	slog.Info("server exited")
+}
+
+// fatal logs err and exits, like log.Fatal.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/database"
+)
+
+const migrateUsage = "usage: server migrate up | down [N] | status | version"
+
+// runMigrate handles `server migrate ...` against the database selected by cfg.Driver.
+// down rolls back one migration unless N is given.
+func runMigrate(cfg config.DatabaseConfig, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	steps := 1
+	switch args[0] {
+	case "up", "status", "version":
+		if len(args) > 1 {
+			return errors.New(migrateUsage)
+		}
+	case "down":
+		if len(args) > 2 {
+			return errors.New(migrateUsage)
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("down expects a positive number of migrations, got %q", args[1])
+			}
+			steps = n
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	migrator, db, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		return migrator.Down(ctx, steps)
+	case "status":
+		migrations, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			state := "pending"
+			if migration.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %s\n", state, migration.Name)
+		}
+		return nil
+	}
+
+	version, dirty, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		fmt.Printf("%d (dirty)\n", version)
+		return nil
+	}
+	fmt.Println(version)
+	return nil
+}
+
+func openMigrator(cfg config.DatabaseConfig) (*database.Migrator, *sql.DB, error) {
+	switch cfg.Driver {
+	case config.DriverMemory:
+		return nil, nil, errors.New("in-memory storage has no schema to migrate")
+	case config.DriverSQLite:
+		db, err := database.ConnectSQLite(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return database.NewSQLiteMigrator(db), db, nil
+	}
+
+	db, err := database.ConnectWithRetry(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return database.NewPostgresMigrator(db), db, nil
+}
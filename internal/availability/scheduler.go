@@ -0,0 +1,50 @@
+package availability
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/services"
+)
+
+// Scheduler reassigns the open reviews of users as soon as their absence begins.
+type Scheduler struct {
+	service *services.Service
+	cfg     config.AbsenceConfig
+}
+
+func NewScheduler(service *services.Service, cfg config.AbsenceConfig) *Scheduler {
+	return &Scheduler{service: service, cfg: cfg}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ProcessStarted(ctx); err != nil {
+				slog.ErrorContext(ctx, "absence reassignment failed", "error", err)
+			}
+		}
+	}
+}
+
+// ProcessStarted handles every absence that has begun since the last run and returns how many were processed.
+func (s *Scheduler) ProcessStarted(ctx context.Context) (int, error) {
+	results, err := s.service.ReassignAbsentReviewers(ctx, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, result := range results {
+		slog.InfoContext(ctx, "absence started", "absence_id", result.AbsenceID, "user_id", result.UserID,
+			"reassigned_prs", len(result.ReassignedPRs), "failed_reassignments", len(result.FailedReassignments))
+	}
+	return len(results), nil
+}
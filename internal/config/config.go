@@ -1,9 +1,12 @@
 package config
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,6 +14,10 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	App      AppConfig
+	Webhooks WebhookConfig
+	Outbox   OutboxConfig
+	Absences AbsenceConfig
+	Stale    StaleReviewConfig
 }
 
 type ServerConfig struct {
@@ -20,25 +27,85 @@ type ServerConfig struct {
 	IdleTimeout  time.Duration
 }
 
+// Storage drivers accepted in DatabaseConfig.Driver.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
 type DatabaseConfig struct {
+	Driver          string
 	Host            string
 	Port            string
 	User            string
 	Password        string
 	Name            string
+	Path            string // SQLite database file
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	MaxRetries      int
 	RetryInterval   time.Duration
+	QueryTimeout    time.Duration
+	AutoMigrate     bool // apply pending Postgres migrations on startup
+}
+
+type WebhookConfig struct {
+	Timeout      time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+type OutboxConfig struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	MaxAttempts     int
+	BaseBackoff     time.Duration
+	MaxBackoff      time.Duration
+	LeaseTimeout    time.Duration
+	LogSink         bool
+	HTTPSinkURL     string
+	HTTPSinkTimeout time.Duration
+}
+
+type AbsenceConfig struct {
+	AutoReassign bool
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+type StaleReviewConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
 }
 
 type AppConfig struct {
-	Env             string
-	ShutdownTimeout time.Duration
+	Env                 string
+	ShutdownTimeout     time.Duration
+	RequestTimeout      time.Duration
+	AdminToken          string
+	AdminTokens         map[string]string // token -> admin name
+	GitHubWebhookSecret string
+	GitLabWebhookToken  string
+	IdempotencyTTL      time.Duration // how long responses are kept for Idempotency-Key retries, 0 turns the keys off
+	LogLevel            slog.Level
 }
 
 func Load() (*Config, error) {
+	adminTokens, err := parseAdminTokens(getEnv("ADMIN_TOKENS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(getEnv("LOG_LEVEL", "info"))); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:         getEnv("PORT", "8080"),
@@ -47,20 +114,59 @@ func Load() (*Config, error) {
 			IdleTimeout:  getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
 		},
 		Database: DatabaseConfig{
+			Driver:          getEnv("DB_DRIVER", DriverPostgres),
 			Host:            getEnv("DB_HOST", "db"),
 			Port:            getEnv("DB_PORT", "5432"),
 			User:            getEnv("DB_USER", "avito_user"),
 			Password:        getEnv("DB_PASSWORD", "avito_pass"),
 			Name:            getEnv("DB_NAME", "avito_db"),
+			Path:            getEnv("DB_PATH", "avito.db"),
 			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 25),
 			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 			MaxRetries:      getEnvAsInt("DB_MAX_RETRIES", 5),
 			RetryInterval:   getEnvAsDuration("DB_RETRY_INTERVAL", 2*time.Second),
+			QueryTimeout:    getEnvAsDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+			AutoMigrate:     getEnvAsBool("DB_AUTO_MIGRATE", false),
 		},
 		App: AppConfig{
-			Env:             getEnv("APP_ENV", "development"),
-			ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+			Env:                 getEnv("APP_ENV", "development"),
+			ShutdownTimeout:     getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+			RequestTimeout:      getEnvAsDuration("REQUEST_TIMEOUT", 8*time.Second),
+			AdminToken:          getEnv("ADMIN_TOKEN", ""),
+			AdminTokens:         adminTokens,
+			GitHubWebhookSecret: getEnv("GITHUB_WEBHOOK_SECRET", ""),
+			GitLabWebhookToken:  getEnv("GITLAB_WEBHOOK_TOKEN", ""),
+			IdempotencyTTL:      getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+			LogLevel:            logLevel,
+		},
+		Webhooks: WebhookConfig{
+			Timeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 6),
+			BaseBackoff:  getEnvAsDuration("WEBHOOK_BASE_BACKOFF", 10*time.Second),
+			MaxBackoff:   getEnvAsDuration("WEBHOOK_MAX_BACKOFF", 30*time.Minute),
+			PollInterval: getEnvAsDuration("WEBHOOK_POLL_INTERVAL", 2*time.Second),
+			BatchSize:    getEnvAsInt("WEBHOOK_BATCH_SIZE", 50),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:    getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:       getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			MaxAttempts:     getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+			BaseBackoff:     getEnvAsDuration("OUTBOX_BASE_BACKOFF", time.Second),
+			MaxBackoff:      getEnvAsDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+			LeaseTimeout:    getEnvAsDuration("OUTBOX_LEASE_TIMEOUT", 5*time.Minute),
+			LogSink:         getEnvAsBool("OUTBOX_LOG_SINK", false),
+			HTTPSinkURL:     getEnv("OUTBOX_HTTP_SINK_URL", ""),
+			HTTPSinkTimeout: getEnvAsDuration("OUTBOX_HTTP_SINK_TIMEOUT", 5*time.Second),
+		},
+		Absences: AbsenceConfig{
+			AutoReassign: getEnvAsBool("ABSENCE_AUTO_REASSIGN", false),
+			PollInterval: getEnvAsDuration("ABSENCE_POLL_INTERVAL", time.Minute),
+			BatchSize:    getEnvAsInt("ABSENCE_BATCH_SIZE", 20),
+		},
+		Stale: StaleReviewConfig{
+			Enabled:      getEnvAsBool("STALE_REVIEW_ENABLED", true),
+			PollInterval: getEnvAsDuration("STALE_REVIEW_POLL_INTERVAL", time.Minute),
 		},
 	}
 
@@ -75,18 +181,114 @@ func (c *Config) validate() error {
 	if c.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
-	if c.Database.Host == "" {
-		return fmt.Errorf("database host is required")
+	switch c.Database.Driver {
+	case DriverPostgres:
+		if c.Database.Host == "" {
+			return fmt.Errorf("database host is required")
+		}
+		if c.Database.Name == "" {
+			return fmt.Errorf("database name is required")
+		}
+	case DriverSQLite:
+		if c.Database.Path == "" {
+			return fmt.Errorf("database path is required")
+		}
+	case DriverMemory:
+	default:
+		return fmt.Errorf("unknown database driver %q", c.Database.Driver)
+	}
+	if c.App.RequestTimeout < 0 {
+		return fmt.Errorf("request timeout must not be negative")
+	}
+	if c.Database.QueryTimeout < 0 {
+		return fmt.Errorf("database query timeout must not be negative")
+	}
+	if c.Webhooks.PollInterval <= 0 {
+		return fmt.Errorf("webhook poll interval must be positive")
+	}
+	if c.Outbox.PollInterval <= 0 {
+		return fmt.Errorf("outbox poll interval must be positive")
 	}
-	if c.Database.Name == "" {
-		return fmt.Errorf("database name is required")
+	if c.Outbox.MaxAttempts <= 0 {
+		return fmt.Errorf("outbox max attempts must be positive")
+	}
+	if c.Outbox.LeaseTimeout <= 0 {
+		return fmt.Errorf("outbox lease timeout must be positive")
+	}
+	if c.Absences.AutoReassign && c.Absences.PollInterval <= 0 {
+		return fmt.Errorf("absence poll interval must be positive")
+	}
+	if c.Stale.Enabled && c.Stale.PollInterval <= 0 {
+		return fmt.Errorf("stale review poll interval must be positive")
 	}
 	return nil
 }
 
+// DefaultAdminName identifies the holder of ADMIN_TOKEN.
+const DefaultAdminName = "admin"
+
+// AdminIdentity returns the name of the admin that owns token. ADMIN_TOKEN belongs to
+// the admin named "admin"; ADMIN_TOKENS gives every other admin a token of their own.
+func (c AppConfig) AdminIdentity(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	identity, found := "", false
+	if c.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.AdminToken)) == 1 {
+		identity, found = DefaultAdminName, true
+	}
+	for adminToken, name := range c.AdminTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+			identity, found = name, true
+		}
+	}
+	return identity, found
+}
+
+// parseAdminTokens reads a comma-separated list of name:token pairs.
+func parseAdminTokens(value string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, token, ok := strings.Cut(entry, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("admin tokens must be name:token pairs")
+		}
+		if _, exists := tokens[token]; exists {
+			return nil, fmt.Errorf("admin token of %q is not unique", name)
+		}
+		tokens[token] = name
+	}
+	return tokens, nil
+}
+
+// ConnectionString also sets statement_timeout, so Postgres aborts any single
+// query running longer than QueryTimeout even if no request context is attached.
 func (c *DatabaseConfig) ConnectionString() string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		c.Host, c.Port, c.User, c.Password, c.Name)
+	if c.QueryTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", c.QueryTimeout.Milliseconds())
+	}
+	return dsn
+}
+
+// SQLiteDSN opens the database file with every transaction taking the write lock up
+// front, which SQLite needs to serialize read-then-write transactions, and with times
+// written as UTC text so that they compare in order.
+func (c *DatabaseConfig) SQLiteDSN() string {
+	busyTimeout := c.QueryTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = 5 * time.Second
+	}
+	return fmt.Sprintf("file:%s?_txlock=immediate&_time_format=sqlite&_timezone=UTC"+
+		"&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)",
+		c.Path, busyTimeout.Milliseconds())
 }
 
 func (c *DatabaseConfig) AlternativeConnectionString() string {
@@ -120,3 +322,11 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
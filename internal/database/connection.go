@@ -3,7 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/lypolix/avito_test/internal/config"
@@ -18,14 +18,14 @@ func ConnectWithRetry(dbConfig config.DatabaseConfig) (*sql.DB, error) {
 	for i := 0; i < dbConfig.MaxRetries; i++ {
 		db, err = sql.Open("postgres", dbConfig.ConnectionString())
 		if err != nil {
-			log.Printf("open db error: %v", err)
+			slog.Warn("open db failed", "attempt", i+1, "error", err)
 			time.Sleep(dbConfig.RetryInterval)
 			continue
 		}
 
 		err = db.Ping()
 		if err != nil {
-			log.Printf("ping db error: %v", err)
+			slog.Warn("ping db failed", "attempt", i+1, "error", err)
 			time.Sleep(dbConfig.RetryInterval)
 			continue
 		}
@@ -34,7 +34,7 @@ func ConnectWithRetry(dbConfig config.DatabaseConfig) (*sql.DB, error) {
 		db.SetMaxIdleConns(dbConfig.MaxIdleConns)
 		db.SetConnMaxLifetime(dbConfig.ConnMaxLifetime)
 
-		log.Printf("db connected")
+		slog.Info("db connected", "attempt", i+1)
 		return db, nil
 	}
 
@@ -0,0 +1,259 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	postgresmigrations "github.com/lypolix/avito_test/migrations"
+	sqlitemigrations "github.com/lypolix/avito_test/migrations/sqlite"
+)
+
+// migrationLockID is the key of the Postgres advisory lock that keeps replicas starting
+// at the same time from applying migrations twice.
+const migrationLockID int64 = 4_151_707_245
+
+// Migration is a pair of NNNNNN_name.up.sql and NNNNNN_name.down.sql files.
+type Migration struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// Migrator applies the migrations built into the server. The version is kept in
+// schema_migrations with the layout golang-migrate uses, a single row with the current
+// version and a dirty flag, so databases migrated with the migrate CLI carry over.
+type Migrator struct {
+	db         *sql.DB
+	migrations fs.FS
+	lock       func(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+}
+
+func NewPostgresMigrator(db *sql.DB) *Migrator {
+	return &Migrator{db: db, migrations: postgresmigrations.FS, lock: advisoryLock}
+}
+
+// NewSQLiteMigrator needs no lock of its own: every transaction takes the write lock
+// on BEGIN.
+func NewSQLiteMigrator(db *sql.DB) *Migrator {
+	return &Migrator{db: db, migrations: sqlitemigrations.FS}
+}
+
+func advisoryLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return nil, fmt.Errorf("lock migrations: %w", err)
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			slog.Error("unlock migrations failed", "error", err)
+		}
+	}, nil
+}
+
+// Up applies every migration newer than the current version, each in its own transaction.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withConn(ctx, true, func(conn *sql.Conn) error {
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		migrations, err := m.list()
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Name+".up.sql", migration.Version); err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "migration applied", "migration", migration.Name)
+		}
+		return nil
+	})
+}
+
+// Down rolls back the given number of migrations, stopping early at an empty schema.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withConn(ctx, true, func(conn *sql.Conn) error {
+		migrations, err := m.list()
+		if err != nil {
+			return err
+		}
+
+		for ; steps > 0; steps-- {
+			current, err := cleanVersion(ctx, conn)
+			if err != nil {
+				return err
+			}
+			if current == 0 {
+				return nil
+			}
+
+			i := sort.Search(len(migrations), func(i int) bool { return migrations[i].Version >= current })
+			if i == len(migrations) || migrations[i].Version != current {
+				return fmt.Errorf("schema version %d is not built into this server", current)
+			}
+			var previous int64
+			if i > 0 {
+				previous = migrations[i-1].Version
+			}
+
+			if err := m.apply(ctx, conn, migrations[i].Name+".down.sql", previous); err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "migration rolled back", "migration", migrations[i].Name)
+		}
+		return nil
+	})
+}
+
+// Version returns the current schema version, 0 for an empty database.
+func (m *Migrator) Version(ctx context.Context) (version int64, dirty bool, err error) {
+	err = m.withConn(ctx, false, func(conn *sql.Conn) error {
+		version, dirty, err = currentVersion(ctx, conn)
+		return err
+	})
+	return version, dirty, err
+}
+
+// Status lists the migrations built into the server and marks those already applied.
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	version, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := m.list()
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrations {
+		migrations[i].Applied = migrations[i].Version <= version
+	}
+	return migrations, nil
+}
+
+// CheckSchema fails if the database is missing migrations this server depends on. A newer
+// schema is accepted, so a rollout can migrate before the old replicas are gone.
+func (m *Migrator) CheckSchema(ctx context.Context) error {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+
+	migrations, err := m.list()
+	if err != nil {
+		return err
+	}
+	if latest := migrations[len(migrations)-1].Version; version < latest {
+		return fmt.Errorf("schema version %d is behind %d, run `server migrate up` or set DB_AUTO_MIGRATE=true", version, latest)
+	}
+	return nil
+}
+
+// withConn runs fn on a single connection, holding the migration lock if asked to, since
+// Postgres advisory locks belong to the session that took them.
+func (m *Migrator) withConn(ctx context.Context, lock bool, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if lock && m.lock != nil {
+		unlock, err := m.lock(ctx, conn)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`)
+	if err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// list returns the migrations ordered by version.
+func (m *Migrator) list() ([]Migration, error) {
+	files, err := fs.Glob(m.migrations, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migrations built into this server")
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(file, ".up.sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", file, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// apply runs the file and moves the schema to version in one transaction; version 0
+// leaves schema_migrations empty, like golang-migrate after the last down migration.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, file string, version int64) error {
+	body, err := fs.ReadFile(m.migrations, file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+		return fmt.Errorf("migration %s: %w", file, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, version); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func currentVersion(ctx context.Context, conn *sql.Conn) (version int64, dirty bool, err error) {
+	err = conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// cleanVersion returns the current version and refuses to go on from a dirty one, which
+// golang-migrate leaves behind when a migration fails halfway.
+func cleanVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty", version)
+	}
+	return version, nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/lypolix/avito_test/internal/config"
+
+	_ "modernc.org/sqlite"
+)
+
+// OpenSQLite opens the database file and brings its schema up to date, so the server
+// needs nothing besides its own binary.
+func OpenSQLite(dbConfig config.DatabaseConfig) (*sql.DB, error) {
+	db, err := ConnectSQLite(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := NewSQLiteMigrator(db).Up(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate sqlite: %w", err)
+	}
+	return db, nil
+}
+
+// ConnectSQLite opens the database file without touching its schema.
+func ConnectSQLite(dbConfig config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dbConfig.SQLiteDSN())
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	db.SetMaxOpenConns(dbConfig.MaxOpenConns)
+	db.SetMaxIdleConns(dbConfig.MaxIdleConns)
+	db.SetConnMaxLifetime(dbConfig.ConnMaxLifetime)
+
+	slog.Info("sqlite opened", "path", dbConfig.Path)
+	return db, nil
+}
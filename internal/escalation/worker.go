@@ -0,0 +1,49 @@
+package escalation
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/services"
+)
+
+// Worker periodically reminds reviewers about overdue reviews and escalates them past the team SLA.
+type Worker struct {
+	service *services.Service
+	cfg     config.StaleReviewConfig
+}
+
+func NewWorker(service *services.Service, cfg config.StaleReviewConfig) *Worker {
+	return &Worker{service: service, cfg: cfg}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.ProcessStale(ctx); err != nil {
+				slog.ErrorContext(ctx, "stale review processing failed", "error", err)
+			}
+		}
+	}
+}
+
+func (w *Worker) ProcessStale(ctx context.Context) error {
+	report, err := w.service.ProcessStaleReviews(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Reminded) > 0 || len(report.Escalated) > 0 || len(report.FailedReassignments) > 0 {
+		slog.InfoContext(ctx, "stale reviews processed", "reminded", len(report.Reminded),
+			"escalated", len(report.Escalated), "failed_reassignments", len(report.FailedReassignments))
+	}
+	return nil
+}
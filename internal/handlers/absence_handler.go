@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AddAbsence(c *gin.Context) {
+	var req models.AddAbsenceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "user_id", req.UserID)
+	absence, err := h.service.AddAbsence(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.AbsenceResponse{Absence: absence})
+}
+
+func (h *Handler) GetAbsences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "user_id query parameter is required",
+			},
+		})
+		return
+	}
+
+	logWith(c, "user_id", userID)
+	response, err := h.service.GetAbsences(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) DeleteAbsence(c *gin.Context) {
+	var req models.DeleteAbsenceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	if err := h.service.DeleteAbsence(c.Request.Context(), req.AbsenceID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"absence_id": req.AbsenceID})
+}
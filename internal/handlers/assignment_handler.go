@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetAssignmentEvents(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	userID := c.Query("user_id")
+	if prID == "" && userID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id or user_id query parameter is required",
+			},
+		})
+		return
+	}
+
+	logWith(c, "pr_id", prID, "user_id", userID)
+	response, err := h.service.GetAssignmentEvents(c.Request.Context(), prID, userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) setExternalAccount(c *gin.Context, provider string) {
+	var req models.SetExternalAccountRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	account, err := h.service.SetExternalAccount(c.Request.Context(), provider, req.Login, req.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ExternalAccountResponse{Account: account})
+}
+
+func (h *Handler) getExternalAccounts(c *gin.Context, provider string) {
+	response, err := h.service.GetExternalAccounts(c.Request.Context(), provider)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) deleteExternalAccount(c *gin.Context, provider string) {
+	var req models.DeleteExternalAccountRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	if err := h.service.DeleteExternalAccount(c.Request.Context(), provider, req.Login); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"login": req.Login})
+}
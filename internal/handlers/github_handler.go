@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxIntegrationPayload = 5 << 20
+
+func (h *Handler) GitHubWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIntegrationPayload))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	if !validGitHubSignature(h.cfg.GitHubWebhookSecret, c.GetHeader("X-Hub-Signature-256"), body) {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "UNAUTHORIZED",
+				Message: "Invalid webhook signature",
+			},
+		})
+		return
+	}
+
+	if c.GetHeader("X-GitHub-Event") != "pull_request" {
+		c.JSON(http.StatusOK, models.IntegrationEventResponse{Result: models.IntegrationIgnored})
+		return
+	}
+
+	var event models.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	response, err := h.service.HandleGitHubPullRequest(c.Request.Context(), &event)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) SetGitHubAccount(c *gin.Context) {
+	h.setExternalAccount(c, models.ProviderGitHub)
+}
+
+func (h *Handler) GetGitHubAccounts(c *gin.Context) {
+	h.getExternalAccounts(c, models.ProviderGitHub)
+}
+
+func (h *Handler) DeleteGitHubAccount(c *gin.Context) {
+	h.deleteExternalAccount(c, models.ProviderGitHub)
+}
+
+// validGitHubSignature checks X-Hub-Signature-256. Without a configured secret every
+// delivery is rejected, since its origin cannot be verified.
+func validGitHubSignature(secret, header string, body []byte) bool {
+	if secret == "" || !strings.HasPrefix(header, "sha256=") {
+		return false
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GitLabWebhook(c *gin.Context) {
+	token := c.GetHeader("X-Gitlab-Token")
+	if h.cfg.GitLabWebhookToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.cfg.GitLabWebhookToken)) != 1 {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "UNAUTHORIZED",
+				Message: "Invalid webhook token",
+			},
+		})
+		return
+	}
+
+	if c.GetHeader("X-Gitlab-Event") != "Merge Request Hook" {
+		c.JSON(http.StatusOK, models.IntegrationEventResponse{Result: models.IntegrationIgnored})
+		return
+	}
+
+	var event models.GitLabMergeRequestEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	response, err := h.service.HandleGitLabMergeRequest(c.Request.Context(), &event)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) SetGitLabAccount(c *gin.Context) {
+	h.setExternalAccount(c, models.ProviderGitLab)
+}
+
+func (h *Handler) GetGitLabAccounts(c *gin.Context) {
+	h.getExternalAccounts(c, models.ProviderGitLab)
+}
+
+func (h *Handler) DeleteGitLabAccount(c *gin.Context) {
+	h.deleteExternalAccount(c, models.ProviderGitLab)
+}
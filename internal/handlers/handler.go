@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 
+	"github.com/lypolix/avito_test/internal/config"
 	"github.com/lypolix/avito_test/internal/models"
 	"github.com/lypolix/avito_test/internal/services"
 
@@ -11,14 +15,15 @@ import (
 
 type Handler struct {
 	service *services.Service
+	cfg     config.AppConfig
 }
 
-func NewHandler(service *services.Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *services.Service, cfg config.AppConfig) *Handler {
+	return &Handler{service: service, cfg: cfg}
 }
 
 func (h *Handler) SetupRoutes() *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
 	h.setupRoutes(router)
 	return router
 }
@@ -27,17 +32,35 @@ func (h *Handler) SetupRoutesWithRouter(router *gin.Engine) {
 	h.setupRoutes(router)
 }
 
+// handleError writes the error response and logs the error with the attributes the
+// handler attached through logWith, such as the PR and user IDs.
 func (h *Handler) handleError(c *gin.Context, err error) {
+	ctx := c.Request.Context()
+
 	if bizErr, ok := err.(*services.BusinessError); ok {
+		slog.InfoContext(ctx, "request rejected", "code", bizErr.Code, "message", bizErr.Message)
 		c.JSON(h.getHTTPStatus(bizErr.Code), models.ErrorResponse{
 			Error: models.ErrorDetail{
 				Code:    bizErr.Code,
 				Message: bizErr.Message,
+				Details: bizErr.Details,
+			},
+		})
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+		slog.WarnContext(ctx, "request timed out", "code", "REQUEST_TIMEOUT", "error", err)
+		c.JSON(http.StatusGatewayTimeout, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "REQUEST_TIMEOUT",
+				Message: "Request was cancelled or exceeded its deadline",
 			},
 		})
 		return
 	}
 
+	slog.ErrorContext(ctx, "request failed", "code", "INTERNAL_ERROR", "error", err)
 	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 		Error: models.ErrorDetail{
 			Code:    "INTERNAL_ERROR",
@@ -46,6 +69,17 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 	})
 }
 
+// requestDeadline bounds every request by RequestTimeout. The context reaches each SQL
+// call, so a request that runs out of time or whose client disconnects has its
+// queries cancelled and its transaction rolled back.
+func (h *Handler) requestDeadline(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.cfg.RequestTimeout)
+	defer cancel()
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
 func (h *Handler) getHTTPStatus(errorCode string) int {
 	switch errorCode {
 	case services.ErrorTeamExists:
@@ -54,8 +88,12 @@ func (h *Handler) getHTTPStatus(errorCode string) int {
 		return http.StatusNotFound
 	case services.ErrorInvalidTeam, services.ErrorUserInOtherTeam:
 		return http.StatusBadRequest
-	case services.ErrorPRMerged, services.ErrorNotAssigned, services.ErrorNoCandidate, services.ErrorPRExists:
+	case services.ErrorPRMerged, services.ErrorNotAssigned, services.ErrorNoCandidate, services.ErrorPRExists,
+		services.ErrorNotEnoughReviewers, services.ErrorReviewerApproved, services.ErrorMergeBlocked, services.ErrorInvalidPRStatus,
+		services.ErrorTeamNotEmpty, services.ErrorRequestInProgress:
 		return http.StatusConflict
+	case services.ErrorIdempotencyKeyReused:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusBadRequest
 	}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotent answers a request that repeats the Idempotency-Key of an earlier one on the
+// same endpoint with the stored response of the earlier request. The first request is
+// handled as usual and its response is kept for IdempotencyTTL. Server errors are not
+// kept, so a request that failed on our side can be retried with the same key.
+func (h *Handler) idempotent(c *gin.Context) {
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" || h.cfg.IdempotencyTTL <= 0 {
+		c.Next()
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.Sum256(body)
+	record := &models.IdempotencyKey{
+		Key:         key,
+		Endpoint:    c.FullPath(),
+		RequestHash: hex.EncodeToString(hash[:]),
+		ExpiresAt:   time.Now().UTC().Add(h.cfg.IdempotencyTTL),
+	}
+
+	stored, err := h.service.ClaimIdempotencyKey(c.Request.Context(), record)
+	if err != nil {
+		h.handleError(c, err)
+		c.Abort()
+		return
+	}
+	if stored != nil {
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.Response)
+		c.Abort()
+		return
+	}
+
+	// The request context may already be done, the outcome is recorded anyway.
+	ctx := context.WithoutCancel(c.Request.Context())
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := h.service.ReleaseIdempotencyKey(ctx, record); err != nil {
+			slog.ErrorContext(ctx, "release idempotency key failed", "idempotency_key", key, "error", err)
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+	if err := h.service.CompleteIdempotencyKey(ctx, record, recorder.Status(), recorder.body.Bytes()); err != nil {
+		slog.ErrorContext(ctx, "store idempotent response failed", "idempotency_key", key, "error", err)
+		return
+	}
+	completed = true
+}
+
+// responseRecorder keeps a copy of the response body written through it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
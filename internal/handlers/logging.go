@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/logging"
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// requestID takes the request ID from X-Request-ID or generates one, echoes it in the
+// response and attaches it to every log line written while the request is handled.
+func requestID(c *gin.Context) {
+	id := c.GetHeader(requestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	c.Header(requestIDHeader, id)
+	c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "request_id", id))
+	c.Next()
+}
+
+// validRequestID accepts IDs of printable ASCII so that a client cannot forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// accessLog writes one line per request once it is handled.
+func accessLog(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	slog.InfoContext(c.Request.Context(), "request",
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"status", c.Writer.Status(),
+		"duration_ms", time.Since(start).Milliseconds(),
+		"client_ip", c.ClientIP(),
+	)
+}
+
+// recovery turns a panic into a 500 response and logs it with the request ID.
+var recovery = gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+	slog.ErrorContext(c.Request.Context(), "panic while handling request", "error", err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Code:    "INTERNAL_ERROR",
+			Message: "Internal server error",
+		},
+	})
+})
+
+// logWith attaches args to the log lines of the rest of the request, including those
+// written by the service and the repository.
+func logWith(c *gin.Context, args ...any) {
+	c.Request = c.Request.WithContext(logging.With(c.Request.Context(), args...))
+}
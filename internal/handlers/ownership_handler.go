@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) ImportOwnership(c *gin.Context) {
+	var req models.ImportOwnershipRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	rules, err := h.service.ImportOwnership(c.Request.Context(), req.Content)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OwnershipRulesResponse{Rules: rules})
+}
+
+func (h *Handler) GetOwnershipRules(c *gin.Context) {
+	rules, err := h.service.GetOwnershipRules(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OwnershipRulesResponse{Rules: rules})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/lypolix/avito_test/internal/models"
@@ -21,7 +22,8 @@ func (h *Handler) CreatePR(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.service.CreatePR(&req)
+	logWith(c, "pr_id", req.PullRequestID, "user_id", req.AuthorID)
+	pr, err := h.service.CreatePR(c.Request.Context(), &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -43,7 +45,34 @@ func (h *Handler) MergePR(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.service.MergePR(req.PullRequestID)
+	logWith(c, "pr_id", req.PullRequestID)
+	if !req.Force {
+		pr, err := h.service.MergePR(c.Request.Context(), req.PullRequestID)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.PRResponse{PR: pr})
+		return
+	}
+
+	admin, ok := h.adminIdentity(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "FORBIDDEN",
+				Message: "Force merge requires admin token",
+			},
+		})
+		return
+	}
+
+	pr, err := h.service.ForceMergePR(c.Request.Context(), req.PullRequestID, &models.MergeOverride{
+		Actor:       admin,
+		RequestedBy: req.ActorID,
+		Reason:      req.Reason,
+	})
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -52,6 +81,43 @@ func (h *Handler) MergePR(c *gin.Context) {
 	c.JSON(http.StatusOK, models.PRResponse{PR: pr})
 }
 
+func (h *Handler) GetMergeOverrides(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id query parameter is required",
+			},
+		})
+		return
+	}
+
+	logWith(c, "pr_id", prID)
+	response, err := h.service.GetMergeOverrides(c.Request.Context(), prID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetStaleReviews(c *gin.Context) {
+	response, err := h.service.GetStaleReviews(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// adminIdentity returns the admin authenticated by the X-Admin-Token header.
+func (h *Handler) adminIdentity(c *gin.Context) (string, bool) {
+	return h.cfg.AdminIdentity(c.GetHeader("X-Admin-Token"))
+}
+
 func (h *Handler) ReassignReviewer(c *gin.Context) {
 	var req models.ReassignReviewerRequest
 
@@ -65,7 +131,53 @@ func (h *Handler) ReassignReviewer(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.ReassignReviewer(req.PullRequestID, req.OldUserID)
+	logWith(c, "pr_id", req.PullRequestID, "user_id", req.OldUserID)
+	response, err := h.service.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.OldUserID, req.Force)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) SubmitReview(c *gin.Context) {
+	var req models.SubmitReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "pr_id", req.PullRequestID, "user_id", req.UserID)
+	response, err := h.service.SubmitReview(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *Handler) GetPRReviews(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id query parameter is required",
+			},
+		})
+		return
+	}
+
+	logWith(c, "pr_id", prID)
+	response, err := h.service.GetPRReviews(c.Request.Context(), prID)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -79,3 +191,38 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 		"status": "OK",
 	})
 }
+
+func (h *Handler) MarkPRReady(c *gin.Context) {
+	h.transitionPR(c, h.service.MarkPRReady)
+}
+
+func (h *Handler) ClosePR(c *gin.Context) {
+	h.transitionPR(c, h.service.ClosePR)
+}
+
+func (h *Handler) ReopenPR(c *gin.Context) {
+	h.transitionPR(c, h.service.ReopenPR)
+}
+
+func (h *Handler) transitionPR(c *gin.Context, transition func(ctx context.Context, prID string) (*models.PullRequest, error)) {
+	var req models.PRLifecycleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "pr_id", req.PullRequestID)
+	pr, err := transition(c.Request.Context(), req.PullRequestID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PRResponse{PR: pr})
+}
@@ -3,16 +3,59 @@ package handlers
 import "github.com/gin-gonic/gin"
 
 func (h *Handler) setupRoutes(router *gin.Engine) {
+	router.Use(requestID, accessLog, recovery)
+	if h.cfg.RequestTimeout > 0 {
+		router.Use(h.requestDeadline)
+	}
+
 	router.POST("/team/add", h.CreateTeam)
 	router.GET("/team/get", h.GetTeam)
+	router.POST("/team/update", h.UpdateTeam)
+	router.POST("/team/members/add", h.AddTeamMembers)
+	router.POST("/team/members/remove", h.RemoveTeamMembers)
+	router.POST("/team/members/move", h.MoveTeamMember)
+	router.POST("/team/rename", h.RenameTeam)
+	router.POST("/team/delete", h.DeleteTeam)
 
 	router.POST("/users/setIsActive", h.SetUserActive)
-	router.POST("/users/bulkDeactivate", h.BulkDeactivateUsers)
+	router.POST("/users/setReviewLimit", h.SetUserReviewLimit)
+	router.POST("/users/bulkDeactivate", h.idempotent, h.BulkDeactivateUsers)
 	router.GET("/users/getReview", h.GetUserPRs)
+	router.POST("/users/absences/add", h.AddAbsence)
+	router.GET("/users/absences", h.GetAbsences)
+	router.POST("/users/absences/delete", h.DeleteAbsence)
 
-	router.POST("/pullRequest/create", h.CreatePR)
+	router.POST("/pullRequest/create", h.idempotent, h.CreatePR)
 	router.POST("/pullRequest/merge", h.MergePR)
-	router.POST("/pullRequest/reassign", h.ReassignReviewer)
+	router.POST("/pullRequest/reassign", h.idempotent, h.ReassignReviewer)
+	router.POST("/pullRequest/ready", h.MarkPRReady)
+	router.POST("/pullRequest/close", h.ClosePR)
+	router.POST("/pullRequest/reopen", h.ReopenPR)
+	router.POST("/pullRequest/review", h.SubmitReview)
+	router.GET("/pullRequest/reviews", h.GetPRReviews)
+	router.GET("/pullRequest/mergeOverrides", h.GetMergeOverrides)
+	router.GET("/pullRequest/stale", h.GetStaleReviews)
+
+	router.GET("/assignments/events", h.GetAssignmentEvents)
+
+	router.POST("/ownership/import", h.ImportOwnership)
+	router.GET("/ownership/rules", h.GetOwnershipRules)
+
+	router.POST("/webhooks/register", h.RegisterWebhook)
+	router.GET("/webhooks/list", h.GetWebhooks)
+	router.POST("/webhooks/delete", h.DeleteWebhook)
+	router.GET("/webhooks/deliveries", h.GetWebhookDeliveries)
+	router.POST("/webhooks/replay", h.ReplayWebhookDelivery)
+
+	router.POST("/integrations/github/webhook", h.GitHubWebhook)
+	router.POST("/integrations/github/users", h.SetGitHubAccount)
+	router.GET("/integrations/github/users", h.GetGitHubAccounts)
+	router.POST("/integrations/github/users/delete", h.DeleteGitHubAccount)
+
+	router.POST("/integrations/gitlab/webhook", h.GitLabWebhook)
+	router.POST("/integrations/gitlab/users", h.SetGitLabAccount)
+	router.GET("/integrations/gitlab/users", h.GetGitLabAccounts)
+	router.POST("/integrations/gitlab/users/delete", h.DeleteGitLabAccount)
 
 	router.GET("/stats", h.GetStats)
 
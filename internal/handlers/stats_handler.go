@@ -7,7 +7,7 @@ import (
 )
 
 func (h *Handler) GetStats(c *gin.Context) {
-	stats, err := h.service.GetStats()
+	stats, err := h.service.GetStats(c.Request.Context())
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -20,7 +20,22 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateTeam(&team); err != nil {
+	logWith(c, "team_name", team.TeamName)
+	if c.Query("upsert") == "true" {
+		response, err := h.service.SyncTeam(c.Request.Context(), &team)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		status := http.StatusOK
+		if response.Created {
+			status = http.StatusCreated
+		}
+		c.JSON(status, response)
+		return
+	}
+
+	if err := h.service.CreateTeam(c.Request.Context(), &team); err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -28,6 +43,28 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 	c.JSON(http.StatusCreated, models.TeamResponse{Team: &team})
 }
 
+func (h *Handler) UpdateTeam(c *gin.Context) {
+	var req models.UpdateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "team_name", req.TeamName)
+	team, err := h.service.UpdateTeam(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TeamResponse{Team: team})
+}
+
 func (h *Handler) GetTeam(c *gin.Context) {
 	teamName := c.Query("team_name")
 	if teamName == "" {
@@ -40,7 +77,13 @@ func (h *Handler) GetTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.service.GetTeam(teamName)
+	logWith(c, "team_name", teamName)
+	getTeam := h.service.GetTeam
+	if c.Query("subtree") == "true" {
+		getTeam = h.service.GetTeamTree
+	}
+
+	team, err := getTeam(c.Request.Context(), teamName)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -48,3 +91,122 @@ func (h *Handler) GetTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, team)
 }
+
+func (h *Handler) AddTeamMembers(c *gin.Context) {
+	var req models.AddTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "team_name", req.TeamName)
+	team, err := h.service.AddTeamMembers(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TeamResponse{Team: team})
+}
+
+func (h *Handler) RemoveTeamMembers(c *gin.Context) {
+	var req models.RemoveTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	if len(req.UserIDs) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "team_name and user_ids are required",
+			},
+		})
+		return
+	}
+
+	logWith(c, "team_name", req.TeamName, "user_ids", req.UserIDs)
+	response, err := h.service.RemoveTeamMembers(c.Request.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) MoveTeamMember(c *gin.Context) {
+	var req models.MoveTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "user_id", req.UserID, "team_name", req.TeamName)
+	response, err := h.service.MoveTeamMember(c.Request.Context(), req.UserID, req.TeamName)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) RenameTeam(c *gin.Context) {
+	var req models.RenameTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "team_name", req.TeamName, "new_name", req.NewName)
+	team, err := h.service.RenameTeam(c.Request.Context(), req.TeamName, req.NewName)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TeamResponse{Team: team})
+}
+
+func (h *Handler) DeleteTeam(c *gin.Context) {
+	var req models.DeleteTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "team_name", req.TeamName)
+	if err := h.service.DeleteTeam(c.Request.Context(), req.TeamName); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team_name": req.TeamName})
+}
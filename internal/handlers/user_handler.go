@@ -31,7 +31,31 @@ func (h *Handler) SetUserActive(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.SetUserActive(req.UserID, req.IsActive)
+	logWith(c, "user_id", req.UserID)
+	user, err := h.service.SetUserActive(c.Request.Context(), req.UserID, req.IsActive)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UserResponse{User: user})
+}
+
+func (h *Handler) SetUserReviewLimit(c *gin.Context) {
+	var req models.SetReviewLimitRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	logWith(c, "user_id", req.UserID)
+	user, err := h.service.SetUserReviewLimit(c.Request.Context(), req.UserID, req.MaxOpenReviews)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -63,7 +87,8 @@ func (h *Handler) BulkDeactivateUsers(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.BulkDeactivateUsers(req.TeamName, req.UserIDs)
+	logWith(c, "team_name", req.TeamName, "user_ids", req.UserIDs)
+	response, err := h.service.BulkDeactivateUsers(c.Request.Context(), req.TeamName, req.UserIDs)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -84,7 +109,8 @@ func (h *Handler) GetUserPRs(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.GetUserPRs(userID)
+	logWith(c, "user_id", userID)
+	response, err := h.service.GetUserPRs(c.Request.Context(), userID)
 	if err != nil {
 		h.handleError(c, err)
 		return
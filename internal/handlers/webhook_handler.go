@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/lypolix/avito_test/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RegisterWebhook(c *gin.Context) {
+	var req models.RegisterWebhookRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	webhook, err := h.service.RegisterWebhook(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.WebhookResponse{Webhook: webhook})
+}
+
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "team_name query parameter is required",
+			},
+		})
+		return
+	}
+
+	response, err := h.service.GetWebhooks(c.Request.Context(), teamName)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	var req models.DeleteWebhookRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), req.WebhookID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhook_id": req.WebhookID})
+}
+
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	webhookID, err := strconv.Atoi(c.Query("webhook_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "webhook_id query parameter is required",
+			},
+		})
+		return
+	}
+
+	response, err := h.service.GetWebhookDeliveries(c.Request.Context(), webhookID, c.Query("status"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) ReplayWebhookDelivery(c *gin.Context) {
+	var req models.ReplayDeliveryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: models.ErrorDetail{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			},
+		})
+		return
+	}
+
+	delivery, err := h.service.ReplayDelivery(c.Request.Context(), req.DeliveryID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.WebhookDeliveryResponse{Delivery: delivery})
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/services"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AbsenceIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestAbsenceIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(AbsenceIntegrationTestSuite))
+}
+
+func (ts *AbsenceIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+}
+
+func (ts *AbsenceIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *AbsenceIntegrationTestSuite) addAbsence(userID string, startsIn, endsIn time.Duration) *models.Absence {
+	now := time.Now()
+	absence, err := ts.suite.Service.AddAbsence(ts.ctx, &models.AddAbsenceRequest{
+		UserID:   userID,
+		StartsAt: now.Add(startsIn),
+		EndsAt:   now.Add(endsIn),
+		Reason:   "vacation",
+	})
+	ts.Require().NoError(err)
+	return absence
+}
+
+func (ts *AbsenceIntegrationTestSuite) TestAddAbsence_Validation() {
+	now := time.Now()
+	_, err := ts.suite.Service.AddAbsence(ts.ctx, &models.AddAbsenceRequest{
+		UserID:   ts.testData.User2,
+		StartsAt: now.Add(time.Hour),
+		EndsAt:   now,
+	})
+	assert.Error(ts.T(), err)
+	assert.Equal(ts.T(), services.ErrorInvalidAbsence, err.(*services.BusinessError).Code)
+
+	_, err = ts.suite.Service.AddAbsence(ts.ctx, &models.AddAbsenceRequest{
+		UserID:   "nonexistent-user",
+		StartsAt: now,
+		EndsAt:   now.Add(time.Hour),
+	})
+	assert.Error(ts.T(), err)
+	assert.Equal(ts.T(), services.ErrorNotFound, err.(*services.BusinessError).Code)
+}
+
+func (ts *AbsenceIntegrationTestSuite) TestCreatePR_SkipsAbsentReviewer() {
+	ts.addAbsence(ts.testData.User2, -time.Hour, 24*time.Hour)
+	ts.addAbsence(ts.testData.User3, 24*time.Hour, 48*time.Hour)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User3}, pr.AssignedReviewers)
+
+	absences, err := ts.suite.Service.GetAbsences(ts.ctx, ts.testData.User3)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), absences.Absences, 1)
+}
+
+func (ts *AbsenceIntegrationTestSuite) TestReassign_SkipsAbsentCandidate() {
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Reviewer3", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+	absence := ts.addAbsence(ts.testData.User4, -time.Minute, time.Hour)
+
+	_, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, false)
+	assert.Error(ts.T(), err)
+	assert.Equal(ts.T(), services.ErrorNoCandidate, err.(*services.BusinessError).Code)
+
+	assert.NoError(ts.T(), ts.suite.Service.DeleteAbsence(ts.ctx, absence.ID))
+
+	response, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, false)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.User4, response.ReplacedBy)
+}
+
+func (ts *AbsenceIntegrationTestSuite) TestReassignAbsentReviewers_OncePerAbsence() {
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Reviewer3", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+	ts.addAbsence(ts.testData.User2, -time.Minute, time.Hour)
+	ts.addAbsence(ts.testData.User3, time.Hour, 2*time.Hour)
+
+	results, err := ts.suite.Service.ReassignAbsentReviewers(ts.ctx, 10)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), results, 1)
+	assert.Equal(ts.T(), ts.testData.User2, results[0].UserID)
+	assert.Len(ts.T(), results[0].ReassignedPRs, 1)
+	assert.Equal(ts.T(), ts.testData.User4, results[0].ReassignedPRs[0].Replacements[0].NewUserID)
+
+	reviewers, err := ts.suite.Repo.GetPRReviewers(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User3, ts.testData.User4}, reviewers)
+
+	events, err := ts.suite.Service.GetAssignmentEvents(ts.ctx, ts.testData.PR1, "")
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), models.OperationAbsence, events.Events[len(events.Events)-1].Operation)
+
+	results, err = ts.suite.Service.ReassignAbsentReviewers(ts.ctx, 10)
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), results)
+}
+
+func (ts *AbsenceIntegrationTestSuite) TestBulkDeactivate_AbsentUser() {
+	ts.addAbsence(ts.testData.User2, -time.Minute, time.Hour)
+
+	response, err := ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{ts.testData.User2})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User2}, response.DeactivatedUsers)
+}
@@ -0,0 +1,201 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConcurrencyIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+	router   http.Handler
+}
+
+func TestConcurrencyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ConcurrencyIntegrationTestSuite))
+}
+
+func (ts *ConcurrencyIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	gin.SetMode(gin.TestMode)
+	ts.router = handlers.NewHandler(ts.suite.Service, config.AppConfig{}).SetupRoutes()
+}
+
+func (ts *ConcurrencyIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *ConcurrencyIntegrationTestSuite) post(path string, body interface{}) int {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		ts.T().Error(err)
+		return 0
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	ts.router.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+// TestConcurrentReassignments_KeepReviewersConsistent runs reassigns on the same PRs
+// alongside a merge racing a close and a bulk deactivation, then checks that every PR
+// still has a consistent reviewer set.
+func (ts *ConcurrencyIntegrationTestSuite) TestConcurrentReassignments_KeepReviewersConsistent() {
+	const (
+		reviewerCount = 10
+		workers       = 4
+		iterations    = 15
+	)
+
+	team := models.Team{
+		TeamName:     ts.testData.Team1,
+		MaxReviewers: 2,
+		Members:      []models.TeamMember{{UserID: ts.testData.User1, Username: "Author", IsActive: true}},
+	}
+	for i := 1; i <= reviewerCount; i++ {
+		userID := fmt.Sprintf("reviewer-%d", i)
+		team.Members = append(team.Members, models.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	ts.Require().NoError(ts.suite.Service.CreateTeam(ts.ctx, &team))
+
+	prIDs := []string{ts.testData.PR1, ts.testData.PR2, "pr-003"}
+	for _, prID := range prIDs {
+		_, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+			PullRequestID:   prID,
+			PullRequestName: prID,
+			AuthorID:        ts.testData.User1,
+		})
+		ts.Require().NoError(err)
+	}
+
+	var wg sync.WaitGroup
+	var mergeCode, closeCode, deactivateCode int
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				prID := prIDs[(w+i)%len(prIDs)]
+				reviewers, err := ts.suite.Repo.GetPRReviewers(ts.ctx, prID)
+				if err != nil {
+					ts.T().Error(err)
+					return
+				}
+				if len(reviewers) == 0 {
+					continue
+				}
+
+				code := ts.post("/pullRequest/reassign", models.ReassignReviewerRequest{
+					PullRequestID: prID,
+					OldUserID:     reviewers[(w+i)%len(reviewers)],
+				})
+				assert.Less(ts.T(), code, http.StatusInternalServerError, "reassign on %s", prID)
+			}
+		}(w)
+	}
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		mergeCode = ts.post("/pullRequest/merge", models.MergePRRequest{PullRequestID: ts.testData.PR1})
+	}()
+	go func() {
+		defer wg.Done()
+		closeCode = ts.post("/pullRequest/close", models.PRLifecycleRequest{PullRequestID: ts.testData.PR1})
+	}()
+	go func() {
+		defer wg.Done()
+		deactivateCode = ts.post("/users/bulkDeactivate", models.BulkDeactivateRequest{
+			TeamName: ts.testData.Team1,
+			UserIDs:  []string{"reviewer-1", "reviewer-2"},
+		})
+	}()
+
+	wg.Wait()
+
+	assert.Equal(ts.T(), http.StatusOK, deactivateCode)
+	assert.True(ts.T(), (mergeCode == http.StatusOK) != (closeCode == http.StatusOK),
+		"exactly one of merge (%d) and close (%d) must win", mergeCode, closeCode)
+
+	pr, err := ts.suite.Repo.GetPR(ts.ctx, ts.testData.PR1)
+	ts.Require().NoError(err)
+	if mergeCode == http.StatusOK {
+		assert.Equal(ts.T(), models.PRStatusMerged, pr.Status)
+		assert.Nil(ts.T(), pr.ClosedAt)
+	} else {
+		assert.Equal(ts.T(), models.PRStatusClosed, pr.Status)
+		assert.Nil(ts.T(), pr.MergedAt)
+	}
+
+	for _, prID := range prIDs {
+		reviewers, err := ts.suite.Repo.GetPRReviewers(ts.ctx, prID)
+		ts.Require().NoError(err)
+
+		assert.Len(ts.T(), reviewers, 2, "reviewer count of %s", prID)
+		assert.NotContains(ts.T(), reviewers, ts.testData.User1, "author reviews %s", prID)
+		assert.ElementsMatch(ts.T(), ts.replayAssignmentEvents(prID), reviewers,
+			"assignment history of %s does not add up to its reviewers", prID)
+	}
+}
+
+// replayAssignmentEvents rebuilds the reviewer set of the PR from its audit log in the
+// order the events were written. A lost update shows up as a step that removes a reviewer
+// who is not assigned or adds one who already is.
+func (ts *ConcurrencyIntegrationTestSuite) replayAssignmentEvents(prID string) []string {
+	response, err := ts.suite.Service.GetAssignmentEvents(ts.ctx, prID, "")
+	ts.Require().NoError(err)
+
+	events := response.Events
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	assigned := make(map[string]bool)
+	add := func(userID string) {
+		assert.False(ts.T(), assigned[userID], "%s assigned twice to %s", userID, prID)
+		assigned[userID] = true
+	}
+	remove := func(userID string) {
+		assert.True(ts.T(), assigned[userID], "%s removed from %s without being assigned", userID, prID)
+		delete(assigned, userID)
+	}
+
+	for _, event := range events {
+		switch event.Action {
+		case models.AssignmentAssigned:
+			add(event.UserID)
+		case models.AssignmentReplaced:
+			remove(event.PreviousUserID)
+			add(event.UserID)
+		case models.AssignmentRemoved:
+			remove(event.UserID)
+		}
+	}
+
+	reviewers := []string{}
+	for userID := range assigned {
+		reviewers = append(reviewers, userID)
+	}
+	return reviewers
+}
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContextIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestContextIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ContextIntegrationTestSuite))
+}
+
+func (ts *ContextIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Reviewer3", ts.testData.Team1, true)
+}
+
+func (ts *ContextIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *ContextIntegrationTestSuite) TestCancelledReassign_LeavesPRUnchanged() {
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1,
+		[]string{ts.testData.User2, ts.testData.User3})
+
+	cancelled, cancel := context.WithCancel(ts.ctx)
+	cancel()
+
+	_, err := ts.suite.Service.ReassignReviewer(cancelled, ts.testData.PR1, ts.testData.User2, false)
+	assert.ErrorIs(ts.T(), err, context.Canceled)
+
+	pr, err := ts.suite.Repo.GetPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User2, ts.testData.User3}, pr.AssignedReviewers)
+
+	events, err := ts.suite.Repo.GetAssignmentEvents(ts.ctx, ts.testData.PR1, "")
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), events)
+}
+
+func (ts *ContextIntegrationTestSuite) TestCancelledTx_RollsBack() {
+	txCtx, cancel := context.WithCancel(ts.ctx)
+
+	tx, err := ts.suite.Repo.BeginTx(txCtx)
+	assert.NoError(ts.T(), err)
+	err = ts.suite.Repo.CreatePRInTx(txCtx, tx, &models.PullRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+		Status:          models.PRStatusOpen,
+	})
+	assert.NoError(ts.T(), err)
+
+	cancel()
+	assert.Error(ts.T(), tx.Commit())
+
+	exists, err := ts.suite.Repo.PRExists(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.False(ts.T(), exists)
+}
+
+func (ts *ContextIntegrationTestSuite) TestExpiredRequestDeadline_ReturnsTimeout() {
+	gin.SetMode(gin.TestMode)
+	router := handlers.NewHandler(ts.suite.Service, config.AppConfig{RequestTimeout: time.Nanosecond}).SetupRoutes()
+
+	body := []byte(`{"pull_request_id": "pr-001", "pull_request_name": "Test PR", "author_id": "user1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(ts.T(), http.StatusGatewayTimeout, recorder.Code)
+	assert.Contains(ts.T(), recorder.Body.String(), "REQUEST_TIMEOUT")
+
+	exists, err := ts.suite.Repo.PRExists(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.False(ts.T(), exists)
+}
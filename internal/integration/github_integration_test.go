@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const githubTestSecret = "github-test-secret"
+
+type GitHubIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+	router   *gin.Engine
+}
+
+func TestGitHubIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(GitHubIntegrationTestSuite))
+}
+
+func (ts *GitHubIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewHandler(ts.suite.Service, config.AppConfig{GitHubWebhookSecret: githubTestSecret})
+	ts.router = handler.SetupRoutes()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Bob", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Carol", ts.testData.Team1, true)
+
+	_, err := ts.suite.Service.SetExternalAccount(ts.ctx, models.ProviderGitHub, "Octo-Alice", ts.testData.User1)
+	ts.Require().NoError(err)
+	_, err = ts.suite.Service.SetExternalAccount(ts.ctx, models.ProviderGitHub, "bob-gh", ts.testData.User2)
+	ts.Require().NoError(err)
+}
+
+func (ts *GitHubIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *GitHubIntegrationTestSuite) deliver(fixture, secret string) (*httptest.ResponseRecorder, models.IntegrationEventResponse) {
+	body, err := os.ReadFile(filepath.Join("testdata", "github", fixture))
+	ts.Require().NoError(err)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/github/webhook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	recorder := httptest.NewRecorder()
+	ts.router.ServeHTTP(recorder, req)
+
+	var response models.IntegrationEventResponse
+	_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func (ts *GitHubIntegrationTestSuite) TestWebhook_RejectsBadSignature() {
+	recorder, _ := ts.deliver("pull_request_opened.json", "wrong-secret")
+	assert.Equal(ts.T(), http.StatusUnauthorized, recorder.Code)
+
+	exists, err := ts.suite.Repo.PRExists(ts.ctx, "octo-org/api#42")
+	assert.NoError(ts.T(), err)
+	assert.False(ts.T(), exists)
+}
+
+func (ts *GitHubIntegrationTestSuite) TestWebhook_OpenedThenMerged() {
+	recorder, response := ts.deliver("pull_request_opened.json", githubTestSecret)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+	assert.Equal(ts.T(), models.IntegrationCreated, response.Result)
+	assert.Equal(ts.T(), "octo-org/api#42", response.PullRequestID)
+	assert.Equal(ts.T(), ts.testData.User1, response.PR.AuthorID)
+	assert.Equal(ts.T(), models.PRStatusOpen, response.PR.Status)
+	assert.Len(ts.T(), response.PR.AssignedReviewers, 2)
+
+	recorder, _ = ts.deliver("pull_request_opened.json", githubTestSecret)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+
+	requiredApprovals := 1
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: ts.testData.Team1, RequiredApprovals: &requiredApprovals})
+	assert.NoError(ts.T(), err)
+
+	recorder, response = ts.deliver("pull_request_closed_merged.json", githubTestSecret)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+	assert.Equal(ts.T(), models.IntegrationMerged, response.Result)
+	assert.Equal(ts.T(), models.PRStatusMerged, response.PR.Status)
+
+	overrides, err := ts.suite.Service.GetMergeOverrides(ts.ctx, "octo-org/api#42")
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), overrides.Overrides, 1)
+	assert.Equal(ts.T(), ts.testData.User2, overrides.Overrides[0].Actor)
+}
+
+func (ts *GitHubIntegrationTestSuite) TestWebhook_DraftLifecycle() {
+	steps := []struct {
+		fixture string
+		result  string
+		status  string
+	}{
+		{"pull_request_opened_draft.json", models.IntegrationCreated, models.PRStatusDraft},
+		{"pull_request_ready_for_review.json", models.IntegrationReady, models.PRStatusOpen},
+		{"pull_request_closed.json", models.IntegrationClosed, models.PRStatusClosed},
+		{"pull_request_reopened.json", models.IntegrationReopened, models.PRStatusOpen},
+	}
+	for _, step := range steps {
+		recorder, response := ts.deliver(step.fixture, githubTestSecret)
+		assert.Equal(ts.T(), http.StatusOK, recorder.Code, step.fixture)
+		assert.Equal(ts.T(), step.result, response.Result, step.fixture)
+		assert.Equal(ts.T(), step.status, response.PR.Status, step.fixture)
+	}
+
+	recorder, response := ts.deliver("pull_request_labeled.json", githubTestSecret)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+	assert.Equal(ts.T(), models.IntegrationIgnored, response.Result)
+}
+
+func (ts *GitHubIntegrationTestSuite) TestWebhook_UnknownAuthor() {
+	assert.NoError(ts.T(), ts.suite.Service.DeleteExternalAccount(ts.ctx, models.ProviderGitHub, "octo-alice"))
+
+	recorder, _ := ts.deliver("pull_request_opened.json", githubTestSecret)
+	assert.Equal(ts.T(), http.StatusBadRequest, recorder.Code)
+	assert.Contains(ts.T(), recorder.Body.String(), "UNKNOWN_ACCOUNT")
+}
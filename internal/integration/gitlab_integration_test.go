@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const gitlabTestToken = "gitlab-test-token"
+
+type GitLabIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+	router   *gin.Engine
+}
+
+func TestGitLabIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(GitLabIntegrationTestSuite))
+}
+
+func (ts *GitLabIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewHandler(ts.suite.Service, config.AppConfig{GitLabWebhookToken: gitlabTestToken})
+	ts.router = handler.SetupRoutes()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Bob", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Carol", ts.testData.Team1, true)
+
+	_, err := ts.suite.Service.SetExternalAccount(ts.ctx, models.ProviderGitLab, "alice.gl", ts.testData.User1)
+	ts.Require().NoError(err)
+	_, err = ts.suite.Service.SetExternalAccount(ts.ctx, models.ProviderGitLab, "bob.gl", ts.testData.User2)
+	ts.Require().NoError(err)
+}
+
+func (ts *GitLabIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *GitLabIntegrationTestSuite) deliver(fixture, token string) (*httptest.ResponseRecorder, models.IntegrationEventResponse) {
+	body, err := os.ReadFile(filepath.Join("testdata", "gitlab", fixture))
+	ts.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/gitlab/webhook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", token)
+
+	recorder := httptest.NewRecorder()
+	ts.router.ServeHTTP(recorder, req)
+
+	var response models.IntegrationEventResponse
+	_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func (ts *GitLabIntegrationTestSuite) TestWebhook_RejectsBadToken() {
+	recorder, _ := ts.deliver("merge_request_open.json", "wrong-token")
+	assert.Equal(ts.T(), http.StatusUnauthorized, recorder.Code)
+
+	exists, err := ts.suite.Repo.PRExists(ts.ctx, "platform/backend/api!7")
+	assert.NoError(ts.T(), err)
+	assert.False(ts.T(), exists)
+}
+
+func (ts *GitLabIntegrationTestSuite) TestWebhook_OpenThenMerge() {
+	recorder, response := ts.deliver("merge_request_open.json", gitlabTestToken)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+	assert.Equal(ts.T(), models.IntegrationCreated, response.Result)
+	assert.Equal(ts.T(), "platform/backend/api!7", response.PullRequestID)
+	assert.Equal(ts.T(), ts.testData.User1, response.PR.AuthorID)
+	assert.Len(ts.T(), response.PR.AssignedReviewers, 2)
+
+	recorder, response = ts.deliver("merge_request_merge.json", gitlabTestToken)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+	assert.Equal(ts.T(), models.IntegrationMerged, response.Result)
+	assert.Equal(ts.T(), models.PRStatusMerged, response.PR.Status)
+}
+
+func (ts *GitLabIntegrationTestSuite) TestWebhook_DraftLifecycle() {
+	steps := []struct {
+		fixture string
+		result  string
+		status  string
+	}{
+		{"merge_request_open_draft.json", models.IntegrationCreated, models.PRStatusDraft},
+		{"merge_request_update_ready.json", models.IntegrationReady, models.PRStatusOpen},
+		{"merge_request_close.json", models.IntegrationClosed, models.PRStatusClosed},
+		{"merge_request_reopen.json", models.IntegrationReopened, models.PRStatusOpen},
+	}
+	for _, step := range steps {
+		recorder, response := ts.deliver(step.fixture, gitlabTestToken)
+		assert.Equal(ts.T(), http.StatusOK, recorder.Code, step.fixture)
+		assert.Equal(ts.T(), step.result, response.Result, step.fixture)
+		assert.Equal(ts.T(), step.status, response.PR.Status, step.fixture)
+	}
+
+	recorder, response := ts.deliver("merge_request_approved.json", gitlabTestToken)
+	assert.Equal(ts.T(), http.StatusOK, recorder.Code)
+	assert.Equal(ts.T(), models.IntegrationIgnored, response.Result)
+}
@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+	router   http.Handler
+}
+
+func TestIdempotencyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyIntegrationTestSuite))
+}
+
+func (ts *IdempotencyIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	gin.SetMode(gin.TestMode)
+	ts.router = handlers.NewHandler(ts.suite.Service, config.AppConfig{IdempotencyTTL: time.Hour}).SetupRoutes()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Reviewer3", ts.testData.Team1, true)
+}
+
+func (ts *IdempotencyIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *IdempotencyIntegrationTestSuite) post(path, key string, body interface{}) *httptest.ResponseRecorder {
+	payload, err := json.Marshal(body)
+	ts.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	recorder := httptest.NewRecorder()
+	ts.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (ts *IdempotencyIntegrationTestSuite) TestReassign_RetryReturnsStoredResponse() {
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+	request := models.ReassignReviewerRequest{PullRequestID: ts.testData.PR1, OldUserID: ts.testData.User2}
+
+	first := ts.post("/pullRequest/reassign", "retry-1", request)
+	ts.Require().Equal(http.StatusOK, first.Code, first.Body.String())
+
+	retry := ts.post("/pullRequest/reassign", "retry-1", request)
+	assert.Equal(ts.T(), http.StatusOK, retry.Code)
+	assert.Equal(ts.T(), "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.JSONEq(ts.T(), first.Body.String(), retry.Body.String())
+
+	events, err := ts.suite.Service.GetAssignmentEvents(ts.ctx, ts.testData.PR1, ts.testData.User2)
+	ts.Require().NoError(err)
+	replaced := 0
+	for _, event := range events.Events {
+		if event.Action == models.AssignmentReplaced {
+			replaced++
+		}
+	}
+	assert.Equal(ts.T(), 1, replaced, "the retry must not reassign again")
+
+	// Without the key the same body is handled as a new request.
+	again := ts.post("/pullRequest/reassign", "", request)
+	assert.Equal(ts.T(), http.StatusConflict, again.Code)
+}
+
+func (ts *IdempotencyIntegrationTestSuite) TestCreatePR_RetryIsNotAConflict() {
+	request := models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	}
+
+	first := ts.post("/pullRequest/create", "create-1", request)
+	ts.Require().Equal(http.StatusCreated, first.Code, first.Body.String())
+
+	retry := ts.post("/pullRequest/create", "create-1", request)
+	assert.Equal(ts.T(), http.StatusCreated, retry.Code)
+	assert.JSONEq(ts.T(), first.Body.String(), retry.Body.String())
+}
+
+func (ts *IdempotencyIntegrationTestSuite) TestKeyReusedWithDifferentRequest() {
+	first := ts.post("/pullRequest/create", "create-1", models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	ts.Require().Equal(http.StatusCreated, first.Code, first.Body.String())
+
+	other := ts.post("/pullRequest/create", "create-1", models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR2,
+		PullRequestName: "Other Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.Equal(ts.T(), http.StatusUnprocessableEntity, other.Code)
+
+	exists, err := ts.suite.Repo.PRExists(ts.ctx, ts.testData.PR2)
+	ts.Require().NoError(err)
+	assert.False(ts.T(), exists)
+
+	// Keys are scoped to the endpoint.
+	deactivate := ts.post("/users/bulkDeactivate", "create-1", models.BulkDeactivateRequest{
+		TeamName: ts.testData.Team1,
+		UserIDs:  []string{ts.testData.User4},
+	})
+	assert.Equal(ts.T(), http.StatusOK, deactivate.Code, deactivate.Body.String())
+}
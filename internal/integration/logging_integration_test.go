@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
+	"github.com/lypolix/avito_test/internal/logging"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoggingIntegrationTestSuite struct {
+	suite.Suite
+	suite         *testutils.IntegrationTestSuite
+	testData      testutils.TestData
+	ctx           context.Context
+	router        http.Handler
+	output        *syncBuffer
+	defaultLogger *slog.Logger
+}
+
+func TestLoggingIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggingIntegrationTestSuite))
+}
+
+func (ts *LoggingIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	gin.SetMode(gin.TestMode)
+	ts.router = handlers.NewHandler(ts.suite.Service, config.AppConfig{}).SetupRoutes()
+
+	ts.output = &syncBuffer{}
+	ts.defaultLogger = slog.Default()
+	slog.SetDefault(logging.New(ts.output, slog.LevelDebug))
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+}
+
+func (ts *LoggingIntegrationTestSuite) TearDownTest() {
+	slog.SetDefault(ts.defaultLogger)
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *LoggingIntegrationTestSuite) post(path, requestID string, body interface{}) *httptest.ResponseRecorder {
+	payload, err := json.Marshal(body)
+	ts.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+	recorder := httptest.NewRecorder()
+	ts.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// records returns the log lines with the given message.
+func (ts *LoggingIntegrationTestSuite) records(msg string) []map[string]interface{} {
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(ts.output.Bytes()))
+	for scanner.Scan() {
+		var record map[string]interface{}
+		ts.Require().NoError(json.Unmarshal(scanner.Bytes(), &record), scanner.Text())
+		if record["msg"] == msg {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (ts *LoggingIntegrationTestSuite) TestRequestID_ReachesServiceLogs() {
+	response := ts.post("/pullRequest/create", "req-create-1", models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	ts.Require().Equal(http.StatusCreated, response.Code)
+	assert.Equal(ts.T(), "req-create-1", response.Header().Get("X-Request-ID"))
+
+	created := ts.records("pull request created")
+	ts.Require().Len(created, 1)
+	assert.Equal(ts.T(), "req-create-1", created[0]["request_id"])
+	assert.Equal(ts.T(), ts.testData.PR1, created[0]["pr_id"])
+	assert.Equal(ts.T(), ts.testData.User1, created[0]["user_id"])
+
+	access := ts.records("request")
+	ts.Require().Len(access, 1)
+	assert.Equal(ts.T(), "req-create-1", access[0]["request_id"])
+	assert.EqualValues(ts.T(), http.StatusCreated, access[0]["status"])
+}
+
+func (ts *LoggingIntegrationTestSuite) TestRequestID_GeneratedWhenMissing() {
+	response := ts.post("/pullRequest/close", "", models.PRLifecycleRequest{PullRequestID: "missing-pr"})
+	ts.Require().Equal(http.StatusNotFound, response.Code)
+
+	requestID := response.Header().Get("X-Request-ID")
+	ts.Require().NotEmpty(requestID)
+
+	rejected := ts.records("request rejected")
+	ts.Require().Len(rejected, 1)
+	assert.Equal(ts.T(), requestID, rejected[0]["request_id"])
+}
+
+func (ts *LoggingIntegrationTestSuite) TestBusinessError_LoggedWithCodeAndIDs() {
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Feature", ts.testData.User1, []string{ts.testData.User2})
+
+	response := ts.post("/pullRequest/reassign", "req-reassign-1", models.ReassignReviewerRequest{
+		PullRequestID: ts.testData.PR1,
+		OldUserID:     ts.testData.User3,
+	})
+	ts.Require().Equal(http.StatusConflict, response.Code)
+
+	rejected := ts.records("request rejected")
+	ts.Require().Len(rejected, 1)
+	assert.Equal(ts.T(), "NOT_ASSIGNED", rejected[0]["code"])
+	assert.Equal(ts.T(), "req-reassign-1", rejected[0]["request_id"])
+	assert.Equal(ts.T(), ts.testData.PR1, rejected[0]["pr_id"])
+	assert.Equal(ts.T(), ts.testData.User3, rejected[0]["user_id"])
+}
+
+func (ts *LoggingIntegrationTestSuite) TestForgedRequestID_IsReplaced() {
+	response := ts.post("/pullRequest/close", "bad\nid", models.PRLifecycleRequest{PullRequestID: "missing-pr"})
+
+	requestID := response.Header().Get("X-Request-ID")
+	assert.NotEmpty(ts.T(), requestID)
+	assert.NotEqual(ts.T(), "bad\nid", requestID)
+}
+
+// syncBuffer is a bytes.Buffer that the logger and the test can share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
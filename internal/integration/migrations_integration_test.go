@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// MigrationsIntegrationTestSuite runs the embedded SQLite migrations on a fresh file,
+// whatever TEST_STORAGE is, since rolling back the shared Postgres test database would
+// break the other suites.
+type MigrationsIntegrationTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator *database.Migrator
+	ctx      context.Context
+}
+
+func TestMigrationsIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrationsIntegrationTestSuite))
+}
+
+func (ts *MigrationsIntegrationTestSuite) SetupTest() {
+	db, err := database.ConnectSQLite(config.DatabaseConfig{
+		Path:         filepath.Join(ts.T().TempDir(), "migrations.db"),
+		MaxOpenConns: 2,
+	})
+	ts.Require().NoError(err)
+
+	ts.db = db
+	ts.migrator = database.NewSQLiteMigrator(db)
+	ts.ctx = context.Background()
+}
+
+func (ts *MigrationsIntegrationTestSuite) TearDownTest() {
+	ts.db.Close()
+}
+
+func (ts *MigrationsIntegrationTestSuite) latestVersion() int64 {
+	migrations, err := ts.migrator.Status(ts.ctx)
+	ts.Require().NoError(err)
+	ts.Require().NotEmpty(migrations)
+	return migrations[len(migrations)-1].Version
+}
+
+func (ts *MigrationsIntegrationTestSuite) TestUp_AppliesEveryMigration() {
+	ts.Error(ts.migrator.CheckSchema(ts.ctx), "an empty database is behind")
+
+	ts.Require().NoError(ts.migrator.Up(ts.ctx))
+	ts.Require().NoError(ts.migrator.Up(ts.ctx), "up is a no-op on a current schema")
+
+	version, dirty, err := ts.migrator.Version(ts.ctx)
+	ts.Require().NoError(err)
+	assert.Equal(ts.T(), ts.latestVersion(), version)
+	assert.False(ts.T(), dirty)
+	assert.NoError(ts.T(), ts.migrator.CheckSchema(ts.ctx))
+
+	migrations, err := ts.migrator.Status(ts.ctx)
+	ts.Require().NoError(err)
+	for _, migration := range migrations {
+		assert.True(ts.T(), migration.Applied, migration.Name)
+	}
+}
+
+func (ts *MigrationsIntegrationTestSuite) TestDown_RollsBackAndUpReapplies() {
+	ts.Require().NoError(ts.migrator.Up(ts.ctx))
+	latest := ts.latestVersion()
+
+	ts.Require().NoError(ts.migrator.Down(ts.ctx, 1))
+	version, _, err := ts.migrator.Version(ts.ctx)
+	ts.Require().NoError(err)
+	assert.Less(ts.T(), version, latest)
+	assert.ErrorContains(ts.T(), ts.migrator.CheckSchema(ts.ctx), "behind")
+
+	migrations, err := ts.migrator.Status(ts.ctx)
+	ts.Require().NoError(err)
+	assert.False(ts.T(), migrations[len(migrations)-1].Applied)
+
+	ts.Require().NoError(ts.migrator.Down(ts.ctx, len(migrations)+1), "down stops at an empty schema")
+	version, _, err = ts.migrator.Version(ts.ctx)
+	ts.Require().NoError(err)
+	assert.Zero(ts.T(), version)
+
+	var tables int
+	ts.Require().NoError(ts.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'teams'`).Scan(&tables))
+	assert.Zero(ts.T(), tables, "the down migrations drop the schema")
+
+	ts.Require().NoError(ts.migrator.Up(ts.ctx))
+	assert.NoError(ts.T(), ts.migrator.CheckSchema(ts.ctx))
+}
+
+func (ts *MigrationsIntegrationTestSuite) TestDirtySchema_IsRefused() {
+	ts.Require().NoError(ts.migrator.Up(ts.ctx))
+	_, err := ts.db.Exec(`UPDATE schema_migrations SET dirty = TRUE`)
+	ts.Require().NoError(err)
+
+	assert.ErrorContains(ts.T(), ts.migrator.CheckSchema(ts.ctx), "dirty")
+	assert.ErrorContains(ts.T(), ts.migrator.Up(ts.ctx), "dirty")
+	assert.ErrorContains(ts.T(), ts.migrator.Down(ts.ctx, 1), "dirty")
+}
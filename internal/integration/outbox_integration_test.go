@@ -0,0 +1,233 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/outbox"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/lypolix/avito_test/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestOutboxIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxIntegrationTestSuite))
+}
+
+func (ts *OutboxIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+}
+
+func (ts *OutboxIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+type flakySink struct {
+	failures int
+	events   []models.Event
+}
+
+func (s *flakySink) Name() string {
+	return "flaky"
+}
+
+func (s *flakySink) Publish(_ context.Context, event models.Event) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (ts *OutboxIntegrationTestSuite) createTeam() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+}
+
+func (ts *OutboxIntegrationTestSuite) eventTypes(events []models.Event) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func (ts *OutboxIntegrationTestSuite) TestDispatch_PublishesInOrder() {
+	ts.createTeam()
+
+	_, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	_, err = ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+
+	channel := outbox.NewChannelSink(10)
+	dispatcher := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100}, channel)
+	published, err := dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 4, published)
+
+	var events []models.Event
+	for i := 0; i < published; i++ {
+		events = append(events, <-channel.Events())
+	}
+	assert.Equal(ts.T(), []string{
+		models.EventPRCreated,
+		models.EventReviewerAssigned,
+		models.EventReviewerAssigned,
+		models.EventPRMerged,
+	}, ts.eventTypes(events))
+	assert.Equal(ts.T(), ts.testData.Team1, events[0].TeamName)
+
+	published, err = dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, published)
+}
+
+func (ts *OutboxIntegrationTestSuite) TestRolledBackWrite_LeavesNoEvent() {
+	ts.createTeam()
+
+	tx, err := ts.suite.Repo.BeginTx(ts.ctx)
+	assert.NoError(ts.T(), err)
+	err = ts.suite.Repo.CreatePRInTx(ts.ctx, tx, &models.PullRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+		Status:          models.PRStatusOpen,
+	})
+	assert.NoError(ts.T(), err)
+	assert.NoError(ts.T(), tx.Rollback())
+
+	sink := &flakySink{}
+	published, err := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100}, sink).DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, published)
+	assert.Empty(ts.T(), sink.events)
+}
+
+func (ts *OutboxIntegrationTestSuite) TestFailedSink_RetriesWithoutSkipping() {
+	ts.createTeam()
+
+	_, err := ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{ts.testData.User2, ts.testData.User3})
+	assert.NoError(ts.T(), err)
+
+	sink := &flakySink{failures: 1}
+	dispatcher := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100, MaxAttempts: 3}, sink)
+
+	published, err := dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, published)
+
+	published, err = dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 2, published)
+	assert.Equal(ts.T(), []string{models.EventUserDeactivated, models.EventUserDeactivated}, ts.eventTypes(sink.events))
+}
+
+func (ts *OutboxIntegrationTestSuite) TestFailedSink_BacksOffWithoutBlockingLaterEvents() {
+	ts.createTeam()
+
+	_, err := ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{ts.testData.User2, ts.testData.User3})
+	assert.NoError(ts.T(), err)
+
+	sink := &flakySink{failures: 1}
+	dispatcher := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{
+		BatchSize:   100,
+		MaxAttempts: 3,
+		BaseBackoff: time.Hour,
+		MaxBackoff:  time.Hour,
+	}, sink)
+
+	published, err := dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, published)
+
+	published, err = dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 1, published, "the event after the failed one is published while the failed one waits")
+}
+
+func (ts *OutboxIntegrationTestSuite) TestFailedSink_DeadLettersAfterMaxAttempts() {
+	ts.createTeam()
+
+	_, err := ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{ts.testData.User2, ts.testData.User3})
+	assert.NoError(ts.T(), err)
+
+	sink := &flakySink{failures: 2}
+	dispatcher := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100, MaxAttempts: 2}, sink)
+
+	for i := 0; i < 2; i++ {
+		published, err := dispatcher.DispatchPending(ts.ctx)
+		assert.NoError(ts.T(), err)
+		assert.Equal(ts.T(), 0, published)
+	}
+
+	published, err := dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 1, published)
+	if assert.Len(ts.T(), sink.events, 1) {
+		assert.Equal(ts.T(), ts.testData.User3, ts.deactivatedUser(sink.events[0]))
+	}
+
+	published, err = dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, published, "the dead-lettered event is not offered again")
+}
+
+func (ts *OutboxIntegrationTestSuite) deactivatedUser(event models.Event) string {
+	payload, err := json.Marshal(event.Data)
+	ts.Require().NoError(err)
+
+	var data models.UserDeactivatedData
+	ts.Require().NoError(json.Unmarshal(payload, &data))
+	return data.UserID
+}
+
+func (ts *OutboxIntegrationTestSuite) TestWebhookSink_IgnoresRepeatedEvent() {
+	ts.createTeam()
+
+	webhook, err := ts.suite.Service.RegisterWebhook(ts.ctx, &models.RegisterWebhookRequest{
+		TeamName:   ts.testData.Team1,
+		URL:        "https://example.com/hook",
+		EventTypes: []string{models.EventUserDeactivated},
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.SetUserActive(ts.ctx, ts.testData.User3, false)
+	assert.NoError(ts.T(), err)
+
+	channel := outbox.NewChannelSink(1)
+	_, err = outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100}, channel).DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	event := <-channel.Events()
+
+	sink := webhooks.NewSink(ts.suite.Repo)
+	assert.NoError(ts.T(), sink.Publish(ts.ctx, event))
+	assert.NoError(ts.T(), sink.Publish(ts.ctx, event))
+
+	deliveries, err := ts.suite.Service.GetWebhookDeliveries(ts.ctx, webhook.ID, "")
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), deliveries.Deliveries, 1)
+	assert.Equal(ts.T(), event.ID, *deliveries.Deliveries[0].OutboxEventID)
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/services"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OwnershipIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestOwnershipIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(OwnershipIntegrationTestSuite))
+}
+
+func (ts *OwnershipIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+}
+
+func (ts *OwnershipIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *OwnershipIntegrationTestSuite) TestImportOwnership_Success() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team1, true)
+
+	rules, err := ts.suite.Service.ImportOwnership(ts.ctx, "# owners\n*.go @user1\n/docs/ @acme/team-alpha\n/vendor/\n")
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), rules, 3)
+	assert.Equal(ts.T(), ts.testData.User1, rules[0].OwnerUserID)
+	assert.Equal(ts.T(), ts.testData.Team1, rules[1].OwnerTeamName)
+	assert.Empty(ts.T(), rules[2].OwnerUserID)
+	assert.Empty(ts.T(), rules[2].OwnerTeamName)
+}
+
+func (ts *OwnershipIntegrationTestSuite) TestImportOwnership_UnknownOwner() {
+	_, err := ts.suite.Service.ImportOwnership(ts.ctx, "*.go @nobody\n")
+	assert.Error(ts.T(), err)
+
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "INVALID_OWNERSHIP", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+}
+
+func (ts *OwnershipIntegrationTestSuite) TestCreatePR_PrefersPathOwners() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Teammate1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Teammate2", ts.testData.Team1, true)
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "DBA", ts.testData.Team2, true)
+
+	_, err := ts.suite.Service.ImportOwnership(ts.ctx, "*.go @user2\n/migrations/ @user4\n")
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Schema change",
+		AuthorID:        ts.testData.User1,
+		ChangedFiles:    []string{"migrations/000010_add_column.up.sql"},
+	})
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), pr.AssignedReviewers, 2)
+	assert.Contains(ts.T(), pr.AssignedReviewers, ts.testData.User4)
+	assert.Equal(ts.T(), []string{ts.testData.User4}, pr.OwnerReviewers)
+
+	storedPR, err := ts.suite.Repo.GetPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{"migrations/000010_add_column.up.sql"}, storedPR.ChangedFiles)
+}
+
+func (ts *OwnershipIntegrationTestSuite) TestMergePR_OwnerApprovalLapsesWhenOwnerReplaced() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Teammate1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Teammate2", ts.testData.Team1, true)
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "DBA", ts.testData.Team2, true)
+
+	requireOwnerApproval := true
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:             ts.testData.Team1,
+		RequireOwnerApproval: &requireOwnerApproval,
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.ImportOwnership(ts.ctx, "/migrations/ @user4\n")
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Schema change",
+		AuthorID:        ts.testData.User1,
+		ChangedFiles:    []string{"migrations/000010_add_column.up.sql"},
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User4,
+		State:         models.ReviewApproved,
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User4, true)
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), services.ErrorMergeBlocked, businessErr.Code)
+		assert.Contains(ts.T(), businessErr.Details, "requires approval from a code owner")
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+}
@@ -1,9 +1,16 @@
 package integration
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/handlers"
 	"github.com/lypolix/avito_test/internal/models"
 	"github.com/lypolix/avito_test/internal/services"
 	"github.com/lypolix/avito_test/internal/testutils"
@@ -44,7 +51,7 @@ func (ts *PRIntegrationTestSuite) TestCreatePR_Success() {
 		AuthorID:        ts.testData.User1,
 	}
 
-	pr, err := ts.suite.Service.CreatePR(prRequest)
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, prRequest)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), pr)
 	assert.Equal(ts.T(), ts.testData.PR1, pr.PullRequestID)
@@ -59,7 +66,7 @@ func (ts *PRIntegrationTestSuite) TestCreatePR_AuthorNotFound() {
 		AuthorID:        "nonexistent-author",
 	}
 
-	pr, err := ts.suite.Service.CreatePR(prRequest)
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, prRequest)
 	assert.Error(ts.T(), err)
 	assert.Nil(ts.T(), pr)
 
@@ -76,12 +83,12 @@ func (ts *PRIntegrationTestSuite) TestMergePR_Success() {
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer", ts.testData.Team1, true)
 	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
 
-	mergedPR, err := ts.suite.Service.MergePR(ts.testData.PR1)
+	mergedPR, err := ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), mergedPR)
 	assert.Equal(ts.T(), "MERGED", mergedPR.Status)
 
-	mergedPR2, err := ts.suite.Service.MergePR(ts.testData.PR1)
+	mergedPR2, err := ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
 	assert.NoError(ts.T(), err)
 	assert.Equal(ts.T(), "MERGED", mergedPR2.Status)
 }
@@ -93,7 +100,7 @@ func (ts *PRIntegrationTestSuite) TestReassignReviewer_Success() {
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
 	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
 
-	reassignResponse, err := ts.suite.Service.ReassignReviewer(ts.testData.PR1, ts.testData.User2)
+	reassignResponse, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, false)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), reassignResponse)
 	assert.NotContains(ts.T(), reassignResponse.PR.AssignedReviewers, ts.testData.User2)
@@ -106,10 +113,517 @@ func (ts *PRIntegrationTestSuite) TestGetStats_Success() {
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "User2", ts.testData.Team1, true)
 	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "PR1", ts.testData.User1, []string{ts.testData.User2})
 
-	stats, err := ts.suite.Service.GetStats()
+	stats, err := ts.suite.Service.GetStats(ts.ctx)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), stats)
 	assert.Greater(ts.T(), stats.Summary.TotalUsers, 0)
 	assert.Greater(ts.T(), stats.Summary.TotalPRs, 0)
 	assert.Greater(ts.T(), stats.Summary.TotalAssignments, 0)
 }
+
+func (ts *PRIntegrationTestSuite) TestCreatePR_LeastLoadedStrategy() {
+	team := models.Team{
+		TeamName:         ts.testData.Team1,
+		ReviewerStrategy: services.StrategyLeastLoaded,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Busy", IsActive: true},
+			{UserID: ts.testData.User3, Username: "Free1", IsActive: true},
+			{UserID: ts.testData.User4, Username: "Free2", IsActive: true},
+		},
+	}
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR2, "Busy PR", ts.testData.User1, []string{ts.testData.User2})
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User3, ts.testData.User4}, pr.AssignedReviewers)
+}
+
+func (ts *PRIntegrationTestSuite) TestReassignReviewer_RoundRobinStrategy() {
+	team := models.Team{
+		TeamName:         ts.testData.Team1,
+		ReviewerStrategy: services.StrategyRoundRobin,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Reviewer1", IsActive: true},
+			{UserID: ts.testData.User3, Username: "Reviewer2", IsActive: true},
+			{UserID: ts.testData.User4, Username: "Reviewer3", IsActive: true},
+		},
+	}
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User2, ts.testData.User3}, pr.AssignedReviewers)
+
+	response, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, false)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.User4, response.ReplacedBy)
+}
+
+func (ts *PRIntegrationTestSuite) TestCreatePR_RoundRobinSurvivesRestart() {
+	team := models.Team{
+		TeamName:         ts.testData.Team1,
+		ReviewerStrategy: services.StrategyRoundRobin,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Reviewer1", IsActive: true},
+			{UserID: ts.testData.User3, Username: "Reviewer2", IsActive: true},
+			{UserID: ts.testData.User4, Username: "Reviewer3", IsActive: true},
+		},
+	}
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User2, ts.testData.User3}, pr.AssignedReviewers)
+
+	restarted := services.NewService(ts.suite.Repo)
+	pr, err = restarted.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR2,
+		PullRequestName: "Another Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User4, ts.testData.User2}, pr.AssignedReviewers)
+}
+
+func (ts *PRIntegrationTestSuite) TestCreatePR_SkipsReviewersAtLimit() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Busy", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Free", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR2, "Busy PR", ts.testData.User3, []string{ts.testData.User2})
+
+	limit := 1
+	_, err := ts.suite.Service.SetUserReviewLimit(ts.ctx, ts.testData.User2, &limit)
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User3}, pr.AssignedReviewers)
+}
+
+func (ts *PRIntegrationTestSuite) TestCreatePR_NotEnoughReviewers() {
+	team := models.Team{
+		TeamName:     ts.testData.Team1,
+		MinReviewers: 2,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Reviewer", IsActive: true},
+			{UserID: ts.testData.User3, Username: "OnLeave", IsActive: false},
+		},
+	}
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.Error(ts.T(), err)
+	assert.Nil(ts.T(), pr)
+
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "NOT_ENOUGH_REVIEWERS", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+
+	exists, err := ts.suite.Repo.PRExists(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.False(ts.T(), exists)
+}
+
+func (ts *PRIntegrationTestSuite) TestCreatePR_FallbackTeam() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Backup1", ts.testData.Team2, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Backup2", ts.testData.Team2, true)
+
+	team := models.Team{
+		TeamName:      ts.testData.Team1,
+		FallbackTeams: []string{ts.testData.Team2},
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "OnLeave", IsActive: false},
+		},
+	}
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User3, ts.testData.User4}, pr.AssignedReviewers)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User3, ts.testData.User4}, pr.FallbackReviewers)
+
+	storedPR, err := ts.suite.Repo.GetPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), pr.FallbackReviewers, storedPR.FallbackReviewers)
+
+	_, err = ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:      ts.testData.Team1,
+		FallbackTeams: []string{},
+	})
+	assert.NoError(ts.T(), err)
+
+	storedPR, err = ts.suite.Repo.GetPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), pr.FallbackReviewers, storedPR.FallbackReviewers,
+		"the fallback flag is recorded at assignment time")
+}
+
+func (ts *PRIntegrationTestSuite) TestSubmitReview_ReassignedReviewerStartsPending() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Spare", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	_, err := ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User2,
+		State:         models.ReviewApproved,
+	})
+	assert.NoError(ts.T(), err)
+
+	response, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, true)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.User4, response.ReplacedBy)
+
+	response, err = ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User4, false)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.User2, response.ReplacedBy)
+
+	states := make(map[string]string)
+	for _, state := range response.PR.ReviewerStates {
+		states[state.UserID] = state.State
+	}
+	assert.Equal(ts.T(), models.ReviewPending, states[ts.testData.User2],
+		"an approval from before the reviewer was assigned again does not count")
+}
+
+func (ts *PRIntegrationTestSuite) TestSubmitReview_LatestStatePerReviewer() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	_, err := ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User2,
+		State:         models.ReviewChangesRequested,
+		Comment:       "please add tests",
+	})
+	assert.NoError(ts.T(), err)
+
+	response, err := ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User2,
+		State:         models.ReviewApproved,
+	})
+	assert.NoError(ts.T(), err)
+
+	states := make(map[string]string)
+	for _, state := range response.PR.ReviewerStates {
+		states[state.UserID] = state.State
+	}
+	assert.Equal(ts.T(), models.ReviewApproved, states[ts.testData.User2])
+	assert.Equal(ts.T(), models.ReviewPending, states[ts.testData.User3])
+
+	history, err := ts.suite.Service.GetPRReviews(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), history.Reviews, 2)
+	assert.Equal(ts.T(), models.ReviewChangesRequested, history.Reviews[0].State)
+
+	_, err = ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User1,
+		State:         models.ReviewApproved,
+	})
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "NOT_ASSIGNED", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+}
+
+func (ts *PRIntegrationTestSuite) TestReassignReviewer_ApprovedRequiresForce() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+
+	_, err := ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User2,
+		State:         models.ReviewApproved,
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, false)
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "REVIEWER_APPROVED", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+
+	response, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, true)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.User3, response.ReplacedBy)
+}
+
+func (ts *PRIntegrationTestSuite) TestMergePR_BlockedByPolicy() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	requiredApprovals, blockOnChanges := 1, true
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:                ts.testData.Team1,
+		RequiredApprovals:       &requiredApprovals,
+		BlockOnChangesRequested: &blockOnChanges,
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User3,
+		State:         models.ReviewChangesRequested,
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "MERGE_BLOCKED", businessErr.Code)
+		assert.Len(ts.T(), businessErr.Details, 2)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+
+	for _, reviewer := range []string{ts.testData.User2, ts.testData.User3} {
+		_, err = ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+			PullRequestID: ts.testData.PR1,
+			UserID:        reviewer,
+			State:         models.ReviewApproved,
+		})
+		assert.NoError(ts.T(), err)
+	}
+
+	pr, err := ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "MERGED", pr.Status)
+}
+
+func (ts *PRIntegrationTestSuite) TestForceMergePR_Audited() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+
+	requiredApprovals := 1
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:          ts.testData.Team1,
+		RequiredApprovals: &requiredApprovals,
+	})
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.ForceMergePR(ts.ctx, ts.testData.PR1, &models.MergeOverride{Actor: "admin", Reason: "hotfix"})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "MERGED", pr.Status)
+
+	response, err := ts.suite.Service.GetMergeOverrides(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), response.Overrides, 1)
+	assert.Equal(ts.T(), "admin", response.Overrides[0].Actor)
+	assert.Equal(ts.T(), "hotfix", response.Overrides[0].Reason)
+	assert.Len(ts.T(), response.Overrides[0].UnmetRules, 1)
+}
+
+func (ts *PRIntegrationTestSuite) TestForceMergePR_ActorIsAuthenticatedAdmin() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+
+	gin.SetMode(gin.TestMode)
+	router := handlers.NewHandler(ts.suite.Service, config.AppConfig{
+		AdminTokens: map[string]string{"lead-token": "lead"},
+	}).SetupRoutes()
+
+	merge := func(token string) int {
+		body, err := json.Marshal(models.MergePRRequest{
+			PullRequestID: ts.testData.PR1,
+			Force:         true,
+			ActorID:       "someone-else",
+			Reason:        "hotfix",
+		})
+		ts.Require().NoError(err)
+
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Token", token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	assert.Equal(ts.T(), http.StatusForbidden, merge("wrong-token"))
+	assert.Equal(ts.T(), http.StatusOK, merge("lead-token"))
+
+	response, err := ts.suite.Service.GetMergeOverrides(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	if assert.Len(ts.T(), response.Overrides, 1) {
+		assert.Equal(ts.T(), "lead", response.Overrides[0].Actor)
+		assert.Equal(ts.T(), "someone-else", response.Overrides[0].RequestedBy)
+	}
+}
+
+func (ts *PRIntegrationTestSuite) TestCreatePR_DraftThenReady() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Draft PR",
+		AuthorID:        ts.testData.User1,
+		Draft:           true,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "DRAFT", pr.Status)
+	assert.Empty(ts.T(), pr.AssignedReviewers)
+
+	_, err = ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "INVALID_PR_STATUS", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+
+	pr, err = ts.suite.Service.MarkPRReady(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "OPEN", pr.Status)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User2, ts.testData.User3}, pr.AssignedReviewers)
+}
+
+func (ts *PRIntegrationTestSuite) TestReopenPR_ReplacesInactiveReviewers() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+
+	pr, err := ts.suite.Service.ClosePR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "CLOSED", pr.Status)
+	assert.NotNil(ts.T(), pr.ClosedAt)
+
+	userPRs, err := ts.suite.Service.GetUserPRs(ts.ctx, ts.testData.User2)
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), userPRs.PullRequests)
+
+	_, err = ts.suite.Service.SetUserActive(ts.ctx, ts.testData.User2, false)
+	assert.NoError(ts.T(), err)
+
+	pr, err = ts.suite.Service.ReopenPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "OPEN", pr.Status)
+	assert.Nil(ts.T(), pr.ClosedAt)
+	assert.Equal(ts.T(), []string{ts.testData.User3}, pr.AssignedReviewers)
+}
+
+func (ts *PRIntegrationTestSuite) TestReopenPR_ReplacesReviewersWhoLeftTheTeam() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+
+	_, err := ts.suite.Service.ClosePR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.MoveTeamMember(ts.ctx, ts.testData.User2, ts.testData.Team2)
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.ReopenPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User3}, pr.AssignedReviewers)
+}
+
+func (ts *PRIntegrationTestSuite) TestAssignmentEvents_History() {
+	team := models.Team{
+		TeamName: ts.testData.Team1,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Reviewer1", IsActive: true},
+			{UserID: ts.testData.User3, Username: "Reviewer2", IsActive: true},
+		},
+	}
+	maxReviewers := 1
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: ts.testData.Team1, MaxReviewers: &maxReviewers})
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), pr.AssignedReviewers, 1)
+	first := pr.AssignedReviewers[0]
+
+	response, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, first, false)
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{first, response.ReplacedBy})
+	assert.NoError(ts.T(), err)
+
+	history, err := ts.suite.Service.GetAssignmentEvents(ts.ctx, ts.testData.PR1, "")
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), history.Events, 3)
+
+	assert.Equal(ts.T(), models.AssignmentAssigned, history.Events[0].Action)
+	assert.Equal(ts.T(), models.OperationCreate, history.Events[0].Operation)
+	assert.Equal(ts.T(), first, history.Events[0].UserID)
+
+	assert.Equal(ts.T(), models.AssignmentReplaced, history.Events[1].Action)
+	assert.Equal(ts.T(), models.OperationReassign, history.Events[1].Operation)
+	assert.Equal(ts.T(), first, history.Events[1].PreviousUserID)
+	assert.Equal(ts.T(), response.ReplacedBy, history.Events[1].UserID)
+
+	assert.Equal(ts.T(), models.AssignmentRemoved, history.Events[2].Action)
+	assert.Equal(ts.T(), models.OperationBulkDeactivate, history.Events[2].Operation)
+	assert.Equal(ts.T(), response.ReplacedBy, history.Events[2].UserID)
+
+	byUser, err := ts.suite.Service.GetAssignmentEvents(ts.ctx, "", first)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), byUser.Events, 2)
+}
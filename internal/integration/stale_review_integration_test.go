@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/outbox"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StaleReviewIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestStaleReviewIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(StaleReviewIntegrationTestSuite))
+}
+
+func (ts *StaleReviewIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Reviewer3", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+}
+
+func (ts *StaleReviewIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *StaleReviewIntegrationTestSuite) setSLA(slaMinutes, escalationMinutes int) {
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:                ts.testData.Team1,
+		ReviewSLAMinutes:        &slaMinutes,
+		ReviewEscalationMinutes: &escalationMinutes,
+	})
+	ts.Require().NoError(err)
+}
+
+func (ts *StaleReviewIntegrationTestSuite) ageAssignment(userID string, minutes int) {
+	ts.suite.AgeAssignment(ts.T(), ts.testData.PR1, userID, minutes)
+}
+
+func (ts *StaleReviewIntegrationTestSuite) TestUpdateTeam_RejectsEscalationBelowSLA() {
+	sla, escalation := 120, 60
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:                ts.testData.Team1,
+		ReviewSLAMinutes:        &sla,
+		ReviewEscalationMinutes: &escalation,
+	})
+	assert.Error(ts.T(), err)
+}
+
+func (ts *StaleReviewIntegrationTestSuite) TestGetStaleReviews_SkipsReviewersWhoActed() {
+	ts.setSLA(60, 0)
+	ts.ageAssignment(ts.testData.User2, 90)
+	ts.ageAssignment(ts.testData.User3, 90)
+
+	_, err := ts.suite.Service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: ts.testData.PR1,
+		UserID:        ts.testData.User3,
+		State:         models.ReviewCommented,
+	})
+	assert.NoError(ts.T(), err)
+
+	response, err := ts.suite.Service.GetStaleReviews(ts.ctx, ts.testData.Team1)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), response.Reviews, 1)
+	assert.Equal(ts.T(), ts.testData.User2, response.Reviews[0].ReviewerID)
+	assert.Nil(ts.T(), response.Reviews[0].EscalateAt)
+}
+
+func (ts *StaleReviewIntegrationTestSuite) TestProcessStaleReviews_RemindsOnce() {
+	ts.setSLA(60, 240)
+	ts.ageAssignment(ts.testData.User2, 90)
+
+	report, err := ts.suite.Service.ProcessStaleReviews(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), report.Reminded, 1)
+	assert.Empty(ts.T(), report.Escalated)
+
+	report, err = ts.suite.Service.ProcessStaleReviews(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), report.Reminded)
+
+	channel := outbox.NewChannelSink(10)
+	published, err := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100}, channel).DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 1, published)
+	assert.Equal(ts.T(), models.EventReviewReminder, (<-channel.Events()).Type)
+}
+
+func (ts *StaleReviewIntegrationTestSuite) TestProcessStaleReviews_EscalatesAfterSecondThreshold() {
+	ts.setSLA(60, 240)
+	ts.ageAssignment(ts.testData.User2, 300)
+
+	report, err := ts.suite.Service.ProcessStaleReviews(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), report.Escalated, 1)
+	assert.Equal(ts.T(), ts.testData.User4, report.Escalated[0].Replacements[0].NewUserID)
+
+	reviewers, err := ts.suite.Repo.GetPRReviewers(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User3, ts.testData.User4}, reviewers)
+
+	events, err := ts.suite.Service.GetAssignmentEvents(ts.ctx, ts.testData.PR1, "")
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), models.OperationEscalation, events.Events[len(events.Events)-1].Operation)
+
+	response, err := ts.suite.Service.GetStaleReviews(ts.ctx, "")
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), response.Reviews)
+}
+
+func (ts *StaleReviewIntegrationTestSuite) TestProcessStaleReviews_ReportsMissingCandidate() {
+	ts.setSLA(60, 240)
+	ts.ageAssignment(ts.testData.User2, 300)
+	_, err := ts.suite.Service.SetUserActive(ts.ctx, ts.testData.User4, false)
+	assert.NoError(ts.T(), err)
+
+	report, err := ts.suite.Service.ProcessStaleReviews(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), report.Escalated)
+	assert.Len(ts.T(), report.FailedReassignments, 1)
+	assert.Equal(ts.T(), ts.testData.User2, report.FailedReassignments[0].OldUserID)
+	assert.Len(ts.T(), report.Reminded, 1)
+	assert.Equal(ts.T(), ts.testData.User2, report.Reminded[0].ReviewerID)
+
+	report, err = ts.suite.Service.ProcessStaleReviews(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Empty(ts.T(), report.FailedReassignments)
+	assert.Empty(ts.T(), report.Reminded)
+
+	response, err := ts.suite.Service.GetStaleReviews(ts.ctx, ts.testData.Team1)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), response.Reviews, 1)
+	assert.False(ts.T(), response.Reviews[0].EscalationDue)
+	assert.NotNil(ts.T(), response.Reviews[0].EscalationAttemptedAt)
+}
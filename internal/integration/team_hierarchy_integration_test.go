@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const testDepartment = "dept-platform"
+
+type TeamHierarchyIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestTeamHierarchyIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamHierarchyIntegrationTestSuite))
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	requiredApprovals := 1
+	ts.Require().NoError(ts.suite.Service.CreateTeam(ts.ctx, &models.Team{
+		TeamName:          testDepartment,
+		RequiredApprovals: requiredApprovals,
+	}))
+	ts.Require().NoError(ts.suite.Service.CreateTeam(ts.ctx, &models.Team{
+		TeamName:      ts.testData.Team1,
+		ParentTeam:    testDepartment,
+		InheritPolicy: true,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Author", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Reviewer1", IsActive: true},
+		},
+	}))
+	ts.Require().NoError(ts.suite.Service.CreateTeam(ts.ctx, &models.Team{
+		TeamName:   ts.testData.Team2,
+		ParentTeam: testDepartment,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User3, Username: "Reviewer2", IsActive: true},
+			{UserID: ts.testData.User4, Username: "Reviewer3", IsActive: true},
+		},
+	}))
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestCreatePR_ClimbsToDepartment() {
+	_, err := ts.suite.Service.AddTeamMembers(ts.ctx, &models.AddTeamMembersRequest{
+		TeamName: testDepartment,
+		Members:  []models.TeamMember{{UserID: "dept-lead", Username: "Lead", IsActive: true}},
+	})
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User2, "dept-lead"}, pr.AssignedReviewers,
+		"members of sibling teams are not candidates of the department")
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestInheritedPolicy() {
+	team, err := ts.suite.Service.GetTeam(ts.ctx, ts.testData.Team1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 1, team.RequiredApprovals)
+	assert.Equal(ts.T(), []string{testDepartment}, team.Ancestors)
+
+	requiredApprovals := 2
+	_, err = ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: testDepartment, RequiredApprovals: &requiredApprovals})
+	assert.NoError(ts.T(), err)
+
+	team, err = ts.suite.Service.GetTeam(ts.ctx, ts.testData.Team1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 2, team.RequiredApprovals)
+
+	requiredApprovals = 0
+	_, err = ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: ts.testData.Team1, RequiredApprovals: &requiredApprovals})
+	assert.Error(ts.T(), err)
+
+	inherit := false
+	team, err = ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:          ts.testData.Team1,
+		InheritPolicy:     &inherit,
+		RequiredApprovals: &requiredApprovals,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, team.RequiredApprovals)
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestUpdateTeam_KeepsOwnSettingsWhileInheriting() {
+	parent := testDepartment
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: ts.testData.Team1, ParentTeam: &parent})
+	assert.NoError(ts.T(), err)
+
+	inherit := false
+	team, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: ts.testData.Team1, InheritPolicy: &inherit})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 0, team.RequiredApprovals, "the inherited value was not written into the team")
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestStaleReviews_UseInheritedSLA() {
+	sla := 60
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: testDepartment, ReviewSLAMinutes: &sla})
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	for _, reviewerID := range pr.AssignedReviewers {
+		ts.suite.AgeAssignment(ts.T(), ts.testData.PR1, reviewerID, 2*sla)
+	}
+
+	stale, err := ts.suite.Service.GetStaleReviews(ts.ctx, ts.testData.Team1)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), stale.Reviews, len(pr.AssignedReviewers))
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestUpdateTeam_RejectsCycle() {
+	parent := ts.testData.Team1
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{TeamName: testDepartment, ParentTeam: &parent})
+	assert.Error(ts.T(), err)
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestGetTeamTree() {
+	team, err := ts.suite.Service.GetTeamTree(ts.ctx, testDepartment)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), team.SubTeams, 2)
+	assert.Empty(ts.T(), team.Members)
+	assert.Len(ts.T(), team.AggregatedMembers, 4)
+}
+
+func (ts *TeamHierarchyIntegrationTestSuite) TestStats_RollUpPerDepartment() {
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	stats, err := ts.suite.Service.GetStats(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), stats.DepartmentStats, 1)
+
+	department := stats.DepartmentStats[0]
+	assert.Equal(ts.T(), testDepartment, department.Department)
+	assert.ElementsMatch(ts.T(), []string{testDepartment, ts.testData.Team1, ts.testData.Team2}, department.Teams)
+	assert.Equal(ts.T(), 4, department.TotalUsers)
+	assert.Equal(ts.T(), 1, department.OpenPRs)
+	assert.Equal(ts.T(), 2, department.OpenAssignmentsCount)
+}
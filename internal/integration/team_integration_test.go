@@ -41,16 +41,16 @@ func (ts *TeamIntegrationTestSuite) TestCreateTeam_Success() {
 		},
 	}
 
-	err := ts.suite.Service.CreateTeam(&team)
+	err := ts.suite.Service.CreateTeam(ts.ctx, &team)
 	assert.NoError(ts.T(), err)
 
-	createdTeam, err := ts.suite.Repo.GetTeam(ts.testData.Team1)
+	createdTeam, err := ts.suite.Repo.GetTeam(ts.ctx, ts.testData.Team1)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), createdTeam)
 	assert.Equal(ts.T(), ts.testData.Team1, createdTeam.TeamName)
 	assert.Len(ts.T(), createdTeam.Members, 2)
 
-	user1, err := ts.suite.Repo.GetUser(ts.testData.User1)
+	user1, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User1)
 	assert.NoError(ts.T(), err)
 	assert.Equal(ts.T(), "Alice", user1.Username)
 	assert.Equal(ts.T(), ts.testData.Team1, user1.TeamName)
@@ -65,10 +65,10 @@ func (ts *TeamIntegrationTestSuite) TestCreateTeam_AlreadyExists() {
 		},
 	}
 
-	err := ts.suite.Service.CreateTeam(&team)
+	err := ts.suite.Service.CreateTeam(ts.ctx, &team)
 	assert.NoError(ts.T(), err)
 
-	err = ts.suite.Service.CreateTeam(&team)
+	err = ts.suite.Service.CreateTeam(ts.ctx, &team)
 	assert.Error(ts.T(), err)
 
 	if businessErr, ok := err.(*services.BusinessError); ok {
@@ -79,7 +79,7 @@ func (ts *TeamIntegrationTestSuite) TestCreateTeam_AlreadyExists() {
 }
 
 func (ts *TeamIntegrationTestSuite) TestGetTeam_NotFound() {
-	team, err := ts.suite.Service.GetTeam("nonexistent-team")
+	team, err := ts.suite.Service.GetTeam(ts.ctx, "nonexistent-team")
 	assert.Error(ts.T(), err)
 	assert.Nil(ts.T(), team)
 
@@ -95,7 +95,11 @@ func (ts *TeamIntegrationTestSuite) TestGetTeam_Success() {
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team1, true)
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Bob", ts.testData.Team1, false)
 
-	team, err := ts.suite.Service.GetTeam(ts.testData.Team1)
+	limit := 3
+	_, err := ts.suite.Service.SetUserReviewLimit(ts.ctx, ts.testData.User1, &limit)
+	assert.NoError(ts.T(), err)
+
+	team, err := ts.suite.Service.GetTeam(ts.ctx, ts.testData.Team1)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), team)
 	assert.Equal(ts.T(), ts.testData.Team1, team.TeamName)
@@ -109,8 +113,166 @@ func (ts *TeamIntegrationTestSuite) TestGetTeam_Success() {
 	assert.Contains(ts.T(), memberMap, ts.testData.User1)
 	assert.Equal(ts.T(), "Alice", memberMap[ts.testData.User1].Username)
 	assert.True(ts.T(), memberMap[ts.testData.User1].IsActive)
+	assert.Equal(ts.T(), &limit, memberMap[ts.testData.User1].MaxOpenReviews)
 
 	assert.Contains(ts.T(), memberMap, ts.testData.User2)
 	assert.Equal(ts.T(), "Bob", memberMap[ts.testData.User2].Username)
 	assert.False(ts.T(), memberMap[ts.testData.User2].IsActive)
+	assert.Nil(ts.T(), memberMap[ts.testData.User2].MaxOpenReviews)
+}
+
+func (ts *TeamIntegrationTestSuite) TestCreateTeam_InvalidStrategy() {
+	team := models.Team{
+		TeamName:         ts.testData.Team1,
+		ReviewerStrategy: "alphabetical",
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Alice", IsActive: true},
+		},
+	}
+
+	err := ts.suite.Service.CreateTeam(ts.ctx, &team)
+	assert.Error(ts.T(), err)
+
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "INVALID_STRATEGY", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+}
+
+func (ts *TeamIntegrationTestSuite) TestUpdateTeam_ReviewerCounts() {
+	team := models.Team{
+		TeamName: ts.testData.Team1,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Alice", IsActive: true},
+		},
+	}
+	assert.NoError(ts.T(), ts.suite.Service.CreateTeam(ts.ctx, &team))
+	assert.Equal(ts.T(), 0, team.MinReviewers)
+	assert.Equal(ts.T(), 2, team.MaxReviewers)
+
+	minReviewers, maxReviewers := 1, 3
+	updated, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:     ts.testData.Team1,
+		MinReviewers: &minReviewers,
+		MaxReviewers: &maxReviewers,
+	})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 1, updated.MinReviewers)
+	assert.Equal(ts.T(), 3, updated.MaxReviewers)
+	assert.Len(ts.T(), updated.Members, 1)
+
+	minReviewers = 4
+	_, err = ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:     ts.testData.Team1,
+		MinReviewers: &minReviewers,
+	})
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), "INVALID_TEAM", businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+}
+
+func (ts *TeamIntegrationTestSuite) TestCreateTeam_TakesOverUserWithoutTeam() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team2, true)
+	_, err := ts.suite.Service.RemoveTeamMembers(ts.ctx, ts.testData.Team2, []string{ts.testData.User1})
+	ts.Require().NoError(err)
+
+	team := models.Team{
+		TeamName: ts.testData.Team1,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Alice", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Bob", IsActive: true},
+		},
+	}
+	ts.Require().NoError(ts.suite.Service.CreateTeam(ts.ctx, &team))
+
+	user, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User1)
+	ts.Require().NoError(err)
+	assert.Equal(ts.T(), ts.testData.Team1, user.TeamName)
+	assert.True(ts.T(), user.IsActive)
+}
+
+func (ts *TeamIntegrationTestSuite) TestSyncTeam_ReportsDiff() {
+	team := models.Team{
+		TeamName: ts.testData.Team1,
+		Members: []models.TeamMember{
+			{UserID: ts.testData.User1, Username: "Alice", IsActive: true},
+			{UserID: ts.testData.User2, Username: "Bob", IsActive: true},
+		},
+	}
+	created, err := ts.suite.Service.SyncTeam(ts.ctx, &team)
+	ts.Require().NoError(err)
+	assert.True(ts.T(), created.Created)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User1, ts.testData.User2}, created.AddedMembers)
+
+	repeated, err := ts.suite.Service.SyncTeam(ts.ctx, &models.Team{TeamName: ts.testData.Team1, Members: []models.TeamMember{
+		{UserID: ts.testData.User1, Username: "Alice", IsActive: true},
+		{UserID: ts.testData.User2, Username: "Bob", IsActive: true},
+	}})
+	ts.Require().NoError(err)
+	assert.False(ts.T(), repeated.Created)
+	assert.Empty(ts.T(), repeated.AddedMembers)
+	assert.Empty(ts.T(), repeated.UpdatedMembers)
+	assert.ElementsMatch(ts.T(), []string{ts.testData.User1, ts.testData.User2}, repeated.UnchangedMembers)
+
+	synced, err := ts.suite.Service.SyncTeam(ts.ctx, &models.Team{TeamName: ts.testData.Team1, Members: []models.TeamMember{
+		{UserID: ts.testData.User1, Username: "Alice Smith", IsActive: true},
+		{UserID: ts.testData.User2, Username: "Bob", IsActive: false},
+		{UserID: ts.testData.User3, Username: "Carol", IsActive: true},
+	}})
+	ts.Require().NoError(err)
+	assert.Equal(ts.T(), []string{ts.testData.User3}, synced.AddedMembers)
+	assert.ElementsMatch(ts.T(), []models.TeamMemberChange{
+		{UserID: ts.testData.User1, Changed: []string{"username"}},
+		{UserID: ts.testData.User2, Changed: []string{"is_active"}},
+	}, synced.UpdatedMembers)
+	assert.Len(ts.T(), synced.Team.Members, 3)
+
+	bob, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User2)
+	ts.Require().NoError(err)
+	assert.False(ts.T(), bob.IsActive)
+}
+
+func (ts *TeamIntegrationTestSuite) TestSyncTeam_ReassignsReviewsOfDeactivatedMembers() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Bob", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Carol", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
+
+	synced, err := ts.suite.Service.SyncTeam(ts.ctx, &models.Team{TeamName: ts.testData.Team1, Members: []models.TeamMember{
+		{UserID: ts.testData.User2, Username: "Bob", IsActive: false},
+	}})
+	ts.Require().NoError(err)
+	ts.Require().Len(synced.ReassignedPRs, 1)
+	assert.Equal(ts.T(), ts.testData.PR1, synced.ReassignedPRs[0].PullRequestID)
+	assert.Equal(ts.T(), []models.UserReplacement{{OldUserID: ts.testData.User2, NewUserID: ts.testData.User3}},
+		synced.ReassignedPRs[0].Replacements)
+
+	reviewers, err := ts.suite.Repo.GetPRReviewers(ts.ctx, ts.testData.PR1)
+	ts.Require().NoError(err)
+	assert.Equal(ts.T(), []string{ts.testData.User3}, reviewers)
+}
+
+func (ts *TeamIntegrationTestSuite) TestSyncTeam_RollsBackOnConflict() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Bob", ts.testData.Team2, true)
+
+	_, err := ts.suite.Service.SyncTeam(ts.ctx, &models.Team{TeamName: ts.testData.Team1, Members: []models.TeamMember{
+		{UserID: ts.testData.User1, Username: "Alice", IsActive: true},
+		{UserID: ts.testData.User2, Username: "Bob", IsActive: true},
+	}})
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), services.ErrorUserInOtherTeam, businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+
+	user, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User1)
+	ts.Require().NoError(err)
+	assert.Nil(ts.T(), user, "the member added before the conflict must be rolled back")
 }
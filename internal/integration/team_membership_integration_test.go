@@ -0,0 +1,176 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/services"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TeamMembershipIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestTeamMembershipIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamMembershipIntegrationTestSuite))
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team2)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) assertCode(err error, code string) {
+	assert.Error(ts.T(), err)
+	if businessErr, ok := err.(*services.BusinessError); ok {
+		assert.Equal(ts.T(), code, businessErr.Code)
+	} else {
+		ts.T().Errorf("Expected BusinessError, got %T", err)
+	}
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestAddTeamMembers() {
+	team, err := ts.suite.Service.AddTeamMembers(ts.ctx, &models.AddTeamMembersRequest{
+		TeamName: ts.testData.Team1,
+		Members:  []models.TeamMember{{UserID: ts.testData.User4, Username: "Dave", IsActive: true}},
+	})
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), team.Members, 4)
+
+	_, err = ts.suite.Service.AddTeamMembers(ts.ctx, &models.AddTeamMembersRequest{
+		TeamName: ts.testData.Team2,
+		Members:  []models.TeamMember{{UserID: ts.testData.User4, Username: "Dave", IsActive: true}},
+	})
+	ts.assertCode(err, services.ErrorUserInOtherTeam)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestRemoveTeamMembers_ReassignsReviews() {
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Reviewer3", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	response, err := ts.suite.Service.RemoveTeamMembers(ts.ctx, ts.testData.Team1, []string{ts.testData.User2})
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User2}, response.RemovedUsers)
+	assert.Len(ts.T(), response.ReassignedPRs, 1)
+	assert.Equal(ts.T(), ts.testData.User4, response.ReassignedPRs[0].Replacements[0].NewUserID)
+
+	user, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User2)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "", user.TeamName)
+	assert.False(ts.T(), user.IsActive)
+
+	team, err := ts.suite.Service.AddTeamMembers(ts.ctx, &models.AddTeamMembersRequest{
+		TeamName: ts.testData.Team2,
+		Members:  []models.TeamMember{{UserID: ts.testData.User2, Username: "Reviewer1", IsActive: true}},
+	})
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), team.Members, 1)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestRemovedAuthor_OpenPRCanStillBeMergedAndReassigned() {
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Spare", ts.testData.Team1, true)
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	requiredApprovals := 1
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:          ts.testData.Team1,
+		RequiredApprovals: &requiredApprovals,
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.RemoveTeamMembers(ts.ctx, ts.testData.Team1, []string{ts.testData.User1})
+	assert.NoError(ts.T(), err)
+
+	response, err := ts.suite.Service.ReassignReviewer(ts.ctx, ts.testData.PR1, ts.testData.User2, false)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.User4, response.ReplacedBy)
+
+	merged, err := ts.suite.Service.MergePR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err, "a removed author's PR falls back to the default policy")
+	assert.Equal(ts.T(), "MERGED", merged.Status)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestMoveTeamMember_ReportsFailedReassignment() {
+	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2, ts.testData.User3})
+
+	response, err := ts.suite.Service.MoveTeamMember(ts.ctx, ts.testData.User3, ts.testData.Team2)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.Team1, response.FromTeam)
+	assert.Equal(ts.T(), ts.testData.Team2, response.ToTeam)
+	assert.Len(ts.T(), response.FailedReassignments, 1)
+
+	reviewers, err := ts.suite.Repo.GetPRReviewers(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), []string{ts.testData.User2}, reviewers)
+
+	user, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User3)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), ts.testData.Team2, user.TeamName)
+	assert.True(ts.T(), user.IsActive)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestRenameTeam() {
+	_, err := ts.suite.Service.RenameTeam(ts.ctx, ts.testData.Team1, ts.testData.Team2)
+	ts.assertCode(err, services.ErrorTeamExists)
+
+	team, err := ts.suite.Service.RenameTeam(ts.ctx, ts.testData.Team1, "team-gamma")
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "team-gamma", team.TeamName)
+	assert.Len(ts.T(), team.Members, 3)
+
+	user, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User1)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), "team-gamma", user.TeamName)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestRenameTeam_KeepsFallbackReviewers() {
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User4, "Author", ts.testData.Team2, true)
+	_, err := ts.suite.Service.UpdateTeam(ts.ctx, &models.UpdateTeamRequest{
+		TeamName:      ts.testData.Team2,
+		FallbackTeams: []string{ts.testData.Team1},
+	})
+	assert.NoError(ts.T(), err)
+
+	pr, err := ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test PR",
+		AuthorID:        ts.testData.User4,
+	})
+	assert.NoError(ts.T(), err)
+	assert.NotEmpty(ts.T(), pr.FallbackReviewers)
+
+	_, err = ts.suite.Service.RenameTeam(ts.ctx, ts.testData.Team1, "team-gamma")
+	assert.NoError(ts.T(), err)
+
+	storedPR, err := ts.suite.Repo.GetPR(ts.ctx, ts.testData.PR1)
+	assert.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), pr.FallbackReviewers, storedPR.FallbackReviewers)
+}
+
+func (ts *TeamMembershipIntegrationTestSuite) TestDeleteTeam_OnlyWhenEmpty() {
+	ts.assertCode(ts.suite.Service.DeleteTeam(ts.ctx, ts.testData.Team1), services.ErrorTeamNotEmpty)
+
+	assert.NoError(ts.T(), ts.suite.Service.DeleteTeam(ts.ctx, ts.testData.Team2))
+
+	exists, err := ts.suite.Repo.TeamExists(ts.ctx, ts.testData.Team2)
+	assert.NoError(ts.T(), err)
+	assert.False(ts.T(), exists)
+}
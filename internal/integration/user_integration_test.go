@@ -35,19 +35,19 @@ func (ts *UserIntegrationTestSuite) TestSetUserActive_Success() {
 	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Alice", ts.testData.Team1, true)
 
-	updatedUser, err := ts.suite.Service.SetUserActive(ts.testData.User1, false)
+	updatedUser, err := ts.suite.Service.SetUserActive(ts.ctx, ts.testData.User1, false)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), updatedUser)
 	assert.Equal(ts.T(), ts.testData.User1, updatedUser.UserID)
 	assert.False(ts.T(), updatedUser.IsActive)
 
-	userFromDB, err := ts.suite.Repo.GetUser(ts.testData.User1)
+	userFromDB, err := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User1)
 	assert.NoError(ts.T(), err)
 	assert.False(ts.T(), userFromDB.IsActive)
 }
 
 func (ts *UserIntegrationTestSuite) TestSetUserActive_NotFound() {
-	user, err := ts.suite.Service.SetUserActive("nonexistent-user", false)
+	user, err := ts.suite.Service.SetUserActive(ts.ctx, "nonexistent-user", false)
 	assert.Error(ts.T(), err)
 	assert.Nil(ts.T(), user)
 
@@ -64,7 +64,7 @@ func (ts *UserIntegrationTestSuite) TestGetUserPRs_Success() {
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer", ts.testData.Team1, true)
 	ts.suite.CreateTestPR(ts.T(), ts.testData.PR1, "Test PR", ts.testData.User1, []string{ts.testData.User2})
 
-	userPRs, err := ts.suite.Service.GetUserPRs(ts.testData.User2)
+	userPRs, err := ts.suite.Service.GetUserPRs(ts.ctx, ts.testData.User2)
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), userPRs)
 	assert.Equal(ts.T(), ts.testData.User2, userPRs.UserID)
@@ -78,15 +78,15 @@ func (ts *UserIntegrationTestSuite) TestBulkDeactivateUsers_Success() {
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "User2", ts.testData.Team1, true)
 	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "User3", ts.testData.Team1, true)
 
-	response, err := ts.suite.Service.BulkDeactivateUsers(ts.testData.Team1, []string{ts.testData.User1, ts.testData.User2})
+	response, err := ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{ts.testData.User1, ts.testData.User2})
 	assert.NoError(ts.T(), err)
 	assert.NotNil(ts.T(), response)
 	assert.Equal(ts.T(), ts.testData.Team1, response.TeamName)
 	assert.Len(ts.T(), response.DeactivatedUsers, 2)
 
-	user1, _ := ts.suite.Repo.GetUser(ts.testData.User1)
-	user2, _ := ts.suite.Repo.GetUser(ts.testData.User2)
-	user3, _ := ts.suite.Repo.GetUser(ts.testData.User3)
+	user1, _ := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User1)
+	user2, _ := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User2)
+	user3, _ := ts.suite.Repo.GetUser(ts.ctx, ts.testData.User3)
 
 	assert.False(ts.T(), user1.IsActive)
 	assert.False(ts.T(), user2.IsActive)
@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/outbox"
+	"github.com/lypolix/avito_test/internal/services"
+	"github.com/lypolix/avito_test/internal/testutils"
+	"github.com/lypolix/avito_test/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookIntegrationTestSuite struct {
+	suite.Suite
+	suite    *testutils.IntegrationTestSuite
+	testData testutils.TestData
+	ctx      context.Context
+}
+
+func TestWebhookIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookIntegrationTestSuite))
+}
+
+func (ts *WebhookIntegrationTestSuite) SetupTest() {
+	ts.suite = testutils.SetupIntegrationTestSuite(ts.T())
+	ts.testData = testutils.GetTestData()
+	ts.ctx = context.Background()
+}
+
+func (ts *WebhookIntegrationTestSuite) TearDownTest() {
+	ts.suite.TearDown(ts.T())
+}
+
+func (ts *WebhookIntegrationTestSuite) workerConfig() config.WebhookConfig {
+	return config.WebhookConfig{
+		Timeout:      2 * time.Second,
+		MaxAttempts:  2,
+		BaseBackoff:  time.Millisecond,
+		MaxBackoff:   time.Second,
+		PollInterval: time.Second,
+		BatchSize:    10,
+	}
+}
+
+func (ts *WebhookIntegrationTestSuite) dispatchOutbox() {
+	dispatcher := outbox.NewDispatcher(ts.suite.Repo, config.OutboxConfig{BatchSize: 100}, webhooks.NewSink(ts.suite.Repo))
+	_, err := dispatcher.DispatchPending(ts.ctx)
+	assert.NoError(ts.T(), err)
+}
+
+func (ts *WebhookIntegrationTestSuite) createTeam() {
+	ts.suite.CreateTestTeam(ts.T(), ts.testData.Team1)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User1, "Author", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User2, "Reviewer1", ts.testData.Team1, true)
+	ts.suite.CreateTestUser(ts.T(), ts.testData.User3, "Reviewer2", ts.testData.Team1, true)
+}
+
+func (ts *WebhookIntegrationTestSuite) TestRegisterWebhook_Validation() {
+	ts.createTeam()
+
+	_, err := ts.suite.Service.RegisterWebhook(ts.ctx, &models.RegisterWebhookRequest{
+		TeamName:   ts.testData.Team1,
+		URL:        "ftp://example.com/hook",
+		EventTypes: []string{models.EventPRCreated},
+	})
+	assert.Equal(ts.T(), services.ErrorInvalidWebhook, err.(*services.BusinessError).Code)
+
+	_, err = ts.suite.Service.RegisterWebhook(ts.ctx, &models.RegisterWebhookRequest{
+		TeamName:   ts.testData.Team1,
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"pr.unknown"},
+	})
+	assert.Equal(ts.T(), services.ErrorInvalidWebhook, err.(*services.BusinessError).Code)
+
+	webhook, err := ts.suite.Service.RegisterWebhook(ts.ctx, &models.RegisterWebhookRequest{
+		TeamName:   ts.testData.Team1,
+		URL:        "https://example.com/hook",
+		EventTypes: []string{models.EventPRCreated, models.EventPRCreated},
+	})
+	assert.NoError(ts.T(), err)
+	assert.NotEmpty(ts.T(), webhook.Secret)
+	assert.Equal(ts.T(), []string{models.EventPRCreated}, webhook.EventTypes)
+
+	list, err := ts.suite.Service.GetWebhooks(ts.ctx, ts.testData.Team1)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), list.Webhooks, 1)
+	assert.Empty(ts.T(), list.Webhooks[0].Secret)
+}
+
+func (ts *WebhookIntegrationTestSuite) TestCreatePR_DeliversSignedEvents() {
+	ts.createTeam()
+
+	var mu sync.Mutex
+	received := map[string][]byte{}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(webhooks.SignatureHeader) != webhooks.Sign("s3cret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		received[r.Header.Get(webhooks.EventHeader)] = body
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	webhook, err := ts.suite.Service.RegisterWebhook(ts.ctx, &models.RegisterWebhookRequest{
+		TeamName:   ts.testData.Team1,
+		URL:        target.URL,
+		Secret:     "s3cret",
+		EventTypes: []string{models.EventPRCreated, models.EventReviewerAssigned},
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   ts.testData.PR1,
+		PullRequestName: "Test Feature",
+		AuthorID:        ts.testData.User1,
+	})
+	assert.NoError(ts.T(), err)
+	ts.dispatchOutbox()
+
+	worker := webhooks.NewWorker(ts.suite.Repo, ts.workerConfig())
+	attempted, err := worker.ProcessDue(ts.ctx)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), 3, attempted)
+
+	var event models.Event
+	assert.NoError(ts.T(), json.Unmarshal(received[models.EventPRCreated], &event))
+	assert.Equal(ts.T(), models.EventPRCreated, event.Type)
+	assert.Equal(ts.T(), ts.testData.Team1, event.TeamName)
+	assert.Contains(ts.T(), received, models.EventReviewerAssigned)
+
+	deliveries, err := ts.suite.Service.GetWebhookDeliveries(ts.ctx, webhook.ID, models.DeliveryDelivered)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), deliveries.Deliveries, 3)
+}
+
+func (ts *WebhookIntegrationTestSuite) TestDelivery_RetriesThenFailsAndReplays() {
+	ts.createTeam()
+
+	var fail atomic.Bool
+	fail.Store(true)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	webhook, err := ts.suite.Service.RegisterWebhook(ts.ctx, &models.RegisterWebhookRequest{
+		TeamName:   ts.testData.Team1,
+		URL:        target.URL,
+		EventTypes: []string{models.EventUserDeactivated},
+	})
+	assert.NoError(ts.T(), err)
+
+	_, err = ts.suite.Service.BulkDeactivateUsers(ts.ctx, ts.testData.Team1, []string{ts.testData.User3})
+	assert.NoError(ts.T(), err)
+	ts.dispatchOutbox()
+
+	worker := webhooks.NewWorker(ts.suite.Repo, ts.workerConfig())
+	for i := 0; i < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		_, err = worker.ProcessDue(ts.ctx)
+		assert.NoError(ts.T(), err)
+	}
+
+	failed, err := ts.suite.Service.GetWebhookDeliveries(ts.ctx, webhook.ID, models.DeliveryFailed)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), failed.Deliveries, 1)
+	assert.Equal(ts.T(), 2, failed.Deliveries[0].Attempts)
+	assert.Equal(ts.T(), http.StatusInternalServerError, *failed.Deliveries[0].ResponseCode)
+
+	fail.Store(false)
+	replay, err := ts.suite.Service.ReplayDelivery(ts.ctx, failed.Deliveries[0].ID)
+	assert.NoError(ts.T(), err)
+	assert.Equal(ts.T(), failed.Deliveries[0].ID, *replay.ReplayOf)
+
+	_, err = worker.ProcessDue(ts.ctx)
+	assert.NoError(ts.T(), err)
+
+	delivered, err := ts.suite.Service.GetWebhookDeliveries(ts.ctx, webhook.ID, models.DeliveryDelivered)
+	assert.NoError(ts.T(), err)
+	assert.Len(ts.T(), delivered.Deliveries, 1)
+	assert.Equal(ts.T(), replay.ID, delivered.Deliveries[0].ID)
+}
+
+func (ts *WebhookIntegrationTestSuite) TestBackoff() {
+	cfg := config.WebhookConfig{BaseBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+
+	assert.Equal(ts.T(), 10*time.Second, webhooks.Backoff(cfg, 1))
+	assert.Equal(ts.T(), 20*time.Second, webhooks.Backoff(cfg, 2))
+	assert.Equal(ts.T(), 40*time.Second, webhooks.Backoff(cfg, 3))
+	assert.Equal(ts.T(), time.Minute, webhooks.Backoff(cfg, 4))
+}
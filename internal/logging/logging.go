@@ -0,0 +1,61 @@
+// Package logging sets up the JSON logger of the server and carries per-request log
+// attributes, such as the request ID, through context.Context.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+)
+
+type attrsKey struct{}
+
+// New returns a JSON logger that adds the attributes attached to the context with With
+// to every record logged through one of the *Context methods.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// With returns a copy of ctx whose log records also carry args, which are given as in
+// slog.Logger.With. An argument replaces an attribute with the same key attached before.
+func With(ctx context.Context, args ...any) context.Context {
+	var record slog.Record
+	record.Add(args...)
+
+	attrs := slices.Clone(attrsFrom(ctx))
+	record.Attrs(func(attr slog.Attr) bool {
+		i := slices.IndexFunc(attrs, func(a slog.Attr) bool { return a.Key == attr.Key })
+		if i >= 0 {
+			attrs[i] = attr
+		} else {
+			attrs = append(attrs, attr)
+		}
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		record.AddAttrs(attrsFrom(ctx)...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Absence is a dated period in which the user is not picked as a reviewer.
+type Absence struct {
+	ID           int64      `json:"id" db:"id"`
+	UserID       string     `json:"user_id" db:"user_id"`
+	StartsAt     time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at" db:"ends_at"`
+	Reason       string     `json:"reason,omitempty" db:"reason"`
+	ReassignedAt *time.Time `json:"reassigned_at,omitempty" db:"reassigned_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (a *Absence) ActiveAt(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
+
+// AbsenceReassignment reports what the scheduler did with the open reviews of a user whose absence began.
+type AbsenceReassignment struct {
+	AbsenceID           int64                `json:"absence_id"`
+	UserID              string               `json:"user_id"`
+	ReassignedPRs       []ReassignedPRDetail `json:"reassigned_prs"`
+	FailedReassignments []FailedReassignment `json:"failed_reassignments,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	AssignmentAssigned = "ASSIGNED"
+	AssignmentReplaced = "REPLACED"
+	AssignmentRemoved  = "REMOVED"
+
+	OperationCreate         = "create"
+	OperationReady          = "ready"
+	OperationReopen         = "reopen"
+	OperationReassign       = "reassign"
+	OperationBulkDeactivate = "bulk_deactivate"
+	OperationAbsence        = "absence"
+	OperationEscalation     = "escalation"
+	OperationTeamRemove     = "team_remove"
+	OperationTeamMove       = "team_move"
+)
+
+type AssignmentEvent struct {
+	ID             int64     `json:"id" db:"id"`
+	PullRequestID  string    `json:"pull_request_id" db:"pr_id"`
+	Action         string    `json:"action" db:"action"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	PreviousUserID string    `json:"previous_user_id,omitempty" db:"previous_user_id"`
+	Operation      string    `json:"operation" db:"operation"`
+	Reason         string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
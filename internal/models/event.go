@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventPRCreated          = "pr.created"
+	EventPROpened           = "pr.opened"
+	EventPRMerged           = "pr.merged"
+	EventPRClosed           = "pr.closed"
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventReviewerRemoved    = "reviewer.removed"
+	EventUserDeactivated    = "user.deactivated"
+	EventReviewReminder     = "review.reminder"
+)
+
+type Event struct {
+	ID         int64       `json:"id"`
+	Type       string      `json:"type"`
+	TeamName   string      `json:"team_name"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type UserDeactivatedData struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+// OutboxEvent is an event stored in the same transaction as the change that caused it.
+// Data holds the encoded Event.Data.
+type OutboxEvent struct {
+	ID          int64           `json:"id" db:"id"`
+	Type        string          `json:"type" db:"event_type"`
+	TeamName    string          `json:"team_name" db:"team_name"`
+	Data        json.RawMessage `json:"data" db:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at" db:"occurred_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty" db:"published_at"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	LastError   string          `json:"last_error,omitempty" db:"last_error"`
+
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	DeadLetteredAt *time.Time `json:"dead_lettered_at,omitempty" db:"dead_lettered_at"`
+}
+
+func (e *OutboxEvent) Event() Event {
+	return Event{
+		ID:         e.ID,
+		Type:       e.Type,
+		TeamName:   e.TeamName,
+		OccurredAt: e.OccurredAt,
+		Data:       e.Data,
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+
+	IntegrationCreated  = "created"
+	IntegrationReady    = "ready"
+	IntegrationMerged   = "merged"
+	IntegrationClosed   = "closed"
+	IntegrationReopened = "reopened"
+	IntegrationIgnored  = "ignored"
+)
+
+// ExternalAccount links a login on a code hosting provider to a user of the service.
+type ExternalAccount struct {
+	Provider  string    `json:"provider" db:"provider"`
+	Login     string    `json:"login" db:"login"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type GitHubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest GitHubPullRequest `json:"pull_request"`
+	Repository  GitHubRepository  `json:"repository"`
+	Sender      GitHubUser        `json:"sender"`
+}
+
+type GitHubPullRequest struct {
+	Number   int         `json:"number"`
+	Title    string      `json:"title"`
+	State    string      `json:"state"`
+	Draft    bool        `json:"draft"`
+	Merged   bool        `json:"merged"`
+	User     GitHubUser  `json:"user"`
+	MergedBy *GitHubUser `json:"merged_by"`
+}
+
+type GitHubRepository struct {
+	FullName string `json:"full_name"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+}
+
+type GitLabMergeRequestEvent struct {
+	ObjectKind       string                 `json:"object_kind"`
+	User             GitLabUser             `json:"user"`
+	Project          GitLabProject          `json:"project"`
+	ObjectAttributes GitLabMergeRequest     `json:"object_attributes"`
+	Changes          GitLabMergeRequestDiff `json:"changes"`
+}
+
+type GitLabMergeRequest struct {
+	IID            int    `json:"iid"`
+	Title          string `json:"title"`
+	State          string `json:"state"`
+	Action         string `json:"action"`
+	Draft          bool   `json:"draft"`
+	WorkInProgress bool   `json:"work_in_progress"`
+}
+
+type GitLabMergeRequestDiff struct {
+	Draft *GitLabBoolChange `json:"draft"`
+}
+
+type GitLabBoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+type GitLabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+type GitLabUser struct {
+	Username string `json:"username"`
+}
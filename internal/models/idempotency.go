@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyKey is the stored outcome of a request sent with an Idempotency-Key header.
+// StatusCode is zero while the first request with the key is still being handled.
+type IdempotencyKey struct {
+	Key         string
+	Endpoint    string
+	RequestHash string
+	StatusCode  int
+	Response    []byte
+	ExpiresAt   time.Time
+}
+
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
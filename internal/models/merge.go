@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type MergeOverride struct {
+	ID            int       `json:"id" db:"id"`
+	PullRequestID string    `json:"pull_request_id" db:"pr_id"`
+	Actor         string    `json:"actor" db:"actor"`
+	RequestedBy   string    `json:"requested_by,omitempty" db:"requested_by"`
+	Reason        string    `json:"reason,omitempty" db:"reason"`
+	UnmetRules    []string  `json:"unmet_rules"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
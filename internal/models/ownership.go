@@ -0,0 +1,9 @@
+package models
+
+type OwnershipRule struct {
+	ID            int    `json:"id" db:"id"`
+	Position      int    `json:"position" db:"position"`
+	Pattern       string `json:"pattern" db:"pattern"`
+	OwnerUserID   string `json:"owner_user_id,omitempty" db:"owner_user_id"`
+	OwnerTeamName string `json:"owner_team_name,omitempty" db:"owner_team_name"`
+}
@@ -2,14 +2,30 @@ package models
 
 import "time"
 
+const (
+	PRStatusDraft  = "DRAFT"
+	PRStatusOpen   = "OPEN"
+	PRStatusMerged = "MERGED"
+	PRStatusClosed = "CLOSED"
+)
+
 type PullRequest struct {
-	PullRequestID     string     `json:"pull_request_id" db:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name" db:"pull_request_name"`
-	AuthorID          string     `json:"author_id" db:"author_id"`
-	Status            string     `json:"status" db:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         time.Time  `json:"-" db:"created_at"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
+	PullRequestID     string          `json:"pull_request_id" db:"pull_request_id"`
+	PullRequestName   string          `json:"pull_request_name" db:"pull_request_name"`
+	AuthorID          string          `json:"author_id" db:"author_id"`
+	Status            string          `json:"status" db:"status"`
+	AssignedReviewers []string        `json:"assigned_reviewers"`
+	FallbackReviewers []string        `json:"fallback_reviewers,omitempty"`
+	OwnerReviewers    []string        `json:"owner_reviewers,omitempty"`
+	ChangedFiles      []string        `json:"changed_files,omitempty"`
+	ReviewerStates    []ReviewerState `json:"reviewer_states,omitempty"`
+	CreatedAt         time.Time       `json:"-" db:"created_at"`
+	MergedAt          *time.Time      `json:"mergedAt,omitempty" db:"merged_at"`
+	ClosedAt          *time.Time      `json:"closedAt,omitempty" db:"closed_at"`
+
+	// ReviewerFallbackTeams maps reviewers drawn from another team to that team. It is
+	// stored with the assignment when the PR is created.
+	ReviewerFallbackTeams map[string]string `json:"-"`
 }
 
 type PullRequestShort struct {
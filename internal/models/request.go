@@ -1,8 +1,36 @@
 package models
 
+import "time"
+
 type CreateTeamRequest struct {
-	TeamName string       `json:"team_name" binding:"required"`
-	Members  []TeamMember `json:"members" binding:"required"`
+	TeamName                string       `json:"team_name" binding:"required"`
+	ParentTeam              string       `json:"parent_team,omitempty"`
+	InheritPolicy           bool         `json:"inherit_policy"`
+	ReviewerStrategy        string       `json:"reviewer_strategy,omitempty"`
+	MinReviewers            int          `json:"min_reviewers"`
+	MaxReviewers            int          `json:"max_reviewers"`
+	FallbackTeams           []string     `json:"fallback_teams"`
+	RequiredApprovals       int          `json:"required_approvals"`
+	BlockOnChangesRequested bool         `json:"block_on_changes_requested"`
+	RequireOwnerApproval    bool         `json:"require_owner_approval"`
+	ReviewSLAMinutes        int          `json:"review_sla_minutes"`
+	ReviewEscalationMinutes int          `json:"review_escalation_minutes"`
+	Members                 []TeamMember `json:"members" binding:"required"`
+}
+
+type UpdateTeamRequest struct {
+	TeamName                string   `json:"team_name" binding:"required"`
+	ParentTeam              *string  `json:"parent_team"`
+	InheritPolicy           *bool    `json:"inherit_policy"`
+	ReviewerStrategy        *string  `json:"reviewer_strategy"`
+	MinReviewers            *int     `json:"min_reviewers"`
+	MaxReviewers            *int     `json:"max_reviewers"`
+	FallbackTeams           []string `json:"fallback_teams"`
+	RequiredApprovals       *int     `json:"required_approvals"`
+	BlockOnChangesRequested *bool    `json:"block_on_changes_requested"`
+	RequireOwnerApproval    *bool    `json:"require_owner_approval"`
+	ReviewSLAMinutes        *int     `json:"review_sla_minutes"`
+	ReviewEscalationMinutes *int     `json:"review_escalation_minutes"`
 }
 
 type SetUserActiveRequest struct {
@@ -10,24 +38,46 @@ type SetUserActiveRequest struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type SetReviewLimitRequest struct {
+	UserID         string `json:"user_id" binding:"required"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
 type BulkDeactivateRequest struct {
 	TeamName string   `json:"team_name" binding:"required"`
 	UserIDs  []string `json:"user_ids" binding:"required"`
 }
 
 type CreatePRRequest struct {
-	PullRequestID   string `json:"pull_request_id" binding:"required"`
-	PullRequestName string `json:"pull_request_name" binding:"required"`
-	AuthorID        string `json:"author_id" binding:"required"`
+	PullRequestID   string   `json:"pull_request_id" binding:"required"`
+	PullRequestName string   `json:"pull_request_name" binding:"required"`
+	AuthorID        string   `json:"author_id" binding:"required"`
+	ChangedFiles    []string `json:"changed_files"`
+	Draft           bool     `json:"draft"`
+}
+
+type PRLifecycleRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required"`
+	Force         bool   `json:"force"`
+	ActorID       string `json:"actor_id"`
+	Reason        string `json:"reason"`
 }
 
 type ReassignReviewerRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required"`
 	OldUserID     string `json:"old_user_id" binding:"required"`
+	Force         bool   `json:"force"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+	UserID        string `json:"user_id" binding:"required"`
+	State         string `json:"state" binding:"required"`
+	Comment       string `json:"comment"`
 }
 
 type GetUserPRsRequest struct {
@@ -37,3 +87,66 @@ type GetUserPRsRequest struct {
 type GetTeamRequest struct {
 	TeamName string `json:"team_name" binding:"required"`
 }
+
+type ImportOwnershipRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type RegisterWebhookRequest struct {
+	TeamName   string   `json:"team_name" binding:"required"`
+	URL        string   `json:"url" binding:"required"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types" binding:"required"`
+}
+
+type DeleteWebhookRequest struct {
+	WebhookID int `json:"webhook_id" binding:"required"`
+}
+
+type ReplayDeliveryRequest struct {
+	DeliveryID int64 `json:"delivery_id" binding:"required"`
+}
+
+type SetExternalAccountRequest struct {
+	Login  string `json:"login" binding:"required"`
+	UserID string `json:"user_id" binding:"required"`
+}
+
+type DeleteExternalAccountRequest struct {
+	Login string `json:"login" binding:"required"`
+}
+
+type AddAbsenceRequest struct {
+	UserID   string    `json:"user_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+type DeleteAbsenceRequest struct {
+	AbsenceID int64 `json:"absence_id" binding:"required"`
+}
+
+type AddTeamMembersRequest struct {
+	TeamName string       `json:"team_name" binding:"required"`
+	Members  []TeamMember `json:"members" binding:"required"`
+}
+
+type RemoveTeamMembersRequest struct {
+	TeamName string   `json:"team_name" binding:"required"`
+	UserIDs  []string `json:"user_ids" binding:"required"`
+}
+
+type MoveTeamMemberRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+type RenameTeamRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	NewName  string `json:"new_name" binding:"required"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+}
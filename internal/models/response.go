@@ -1,8 +1,9 @@
 package models
 
 type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -13,6 +14,23 @@ type TeamResponse struct {
 	Team *Team `json:"team"`
 }
 
+// TeamSyncResponse reports what POST /team/add?upsert=true changed.
+type TeamSyncResponse struct {
+	Team                *Team                `json:"team"`
+	Created             bool                 `json:"created"`
+	AddedMembers        []string             `json:"added_members"`
+	UpdatedMembers      []TeamMemberChange   `json:"updated_members"`
+	UnchangedMembers    []string             `json:"unchanged_members"`
+	ReassignedPRs       []ReassignedPRDetail `json:"reassigned_prs"`
+	FailedReassignments []FailedReassignment `json:"failed_reassignments,omitempty"`
+}
+
+// TeamMemberChange names the fields of a member that a sync changed.
+type TeamMemberChange struct {
+	UserID  string   `json:"user_id"`
+	Changed []string `json:"changed"`
+}
+
 type UserResponse struct {
 	User *User `json:"user"`
 }
@@ -22,8 +40,79 @@ type PRResponse struct {
 }
 
 type ReassignResponse struct {
-	PR         *PullRequest `json:"pr"`
-	ReplacedBy string       `json:"replaced_by"`
+	PR           *PullRequest `json:"pr"`
+	ReplacedBy   string       `json:"replaced_by"`
+	FallbackTeam string       `json:"fallback_team,omitempty"`
+}
+
+type OwnershipRulesResponse struct {
+	Rules []OwnershipRule `json:"rules"`
+}
+
+type ReviewResponse struct {
+	Review *Review      `json:"review"`
+	PR     *PullRequest `json:"pr"`
+}
+
+type ReviewsResponse struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Reviews       []Review `json:"reviews"`
+}
+
+type AssignmentEventsResponse struct {
+	Events []AssignmentEvent `json:"events"`
+}
+
+type WebhookResponse struct {
+	Webhook *Webhook `json:"webhook"`
+}
+
+type WebhooksResponse struct {
+	TeamName string    `json:"team_name"`
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type WebhookDeliveryResponse struct {
+	Delivery *WebhookDelivery `json:"delivery"`
+}
+
+type WebhookDeliveriesResponse struct {
+	WebhookID  int               `json:"webhook_id"`
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+type ExternalAccountResponse struct {
+	Account *ExternalAccount `json:"account"`
+}
+
+type ExternalAccountsResponse struct {
+	Provider string            `json:"provider"`
+	Accounts []ExternalAccount `json:"accounts"`
+}
+
+// IntegrationEventResponse tells the provider what the service did with a delivered event.
+type IntegrationEventResponse struct {
+	Result        string       `json:"result"`
+	PullRequestID string       `json:"pull_request_id,omitempty"`
+	PR            *PullRequest `json:"pr,omitempty"`
+}
+
+type AbsenceResponse struct {
+	Absence *Absence `json:"absence"`
+}
+
+type AbsencesResponse struct {
+	UserID   string    `json:"user_id"`
+	Absences []Absence `json:"absences"`
+}
+
+type StaleReviewsResponse struct {
+	Reviews []StaleReview `json:"reviews"`
+}
+
+type MergeOverridesResponse struct {
+	PullRequestID string          `json:"pull_request_id"`
+	Overrides     []MergeOverride `json:"overrides"`
 }
 
 type UserPRsResponse struct {
@@ -38,14 +127,30 @@ type BulkDeactivateResponse struct {
 	FailedReassignments []FailedReassignment `json:"failed_reassignments,omitempty"`
 }
 
+type RemoveTeamMembersResponse struct {
+	TeamName            string               `json:"team_name"`
+	RemovedUsers        []string             `json:"removed_users"`
+	ReassignedPRs       []ReassignedPRDetail `json:"reassigned_prs"`
+	FailedReassignments []FailedReassignment `json:"failed_reassignments,omitempty"`
+}
+
+type MoveTeamMemberResponse struct {
+	UserID              string               `json:"user_id"`
+	FromTeam            string               `json:"from_team"`
+	ToTeam              string               `json:"to_team"`
+	ReassignedPRs       []ReassignedPRDetail `json:"reassigned_prs"`
+	FailedReassignments []FailedReassignment `json:"failed_reassignments,omitempty"`
+}
+
 type ReassignedPRDetail struct {
 	PullRequestID string            `json:"pull_request_id"`
 	Replacements  []UserReplacement `json:"replacements"`
 }
 
 type UserReplacement struct {
-	OldUserID string `json:"old_user_id"`
-	NewUserID string `json:"new_user_id,omitempty"`
+	OldUserID    string `json:"old_user_id"`
+	NewUserID    string `json:"new_user_id,omitempty"`
+	FallbackTeam string `json:"fallback_team,omitempty"`
 }
 
 type FailedReassignment struct {
@@ -55,17 +160,19 @@ type FailedReassignment struct {
 }
 
 type StatsResponse struct {
-	UserStats []UserStat   `json:"user_stats"`
-	PRStats   []PRStat     `json:"pr_stats"`
-	Summary   StatsSummary `json:"summary"`
+	UserStats       []UserStat       `json:"user_stats"`
+	PRStats         []PRStat         `json:"pr_stats"`
+	DepartmentStats []DepartmentStat `json:"department_stats"`
+	Summary         StatsSummary     `json:"summary"`
 }
 
 type UserStat struct {
-	UserID           string `json:"user_id"`
-	Username         string `json:"username"`
-	TeamName         string `json:"team_name"`
-	IsActive         bool   `json:"is_active"`
-	AssignmentsCount int    `json:"assignments_count"`
+	UserID               string `json:"user_id"`
+	Username             string `json:"username"`
+	TeamName             string `json:"team_name"`
+	IsActive             bool   `json:"is_active"`
+	AssignmentsCount     int    `json:"assignments_count"`
+	OpenAssignmentsCount int    `json:"open_assignments_count"`
 }
 
 type PRStat struct {
@@ -77,11 +184,24 @@ type PRStat struct {
 	TeamName        string `json:"team_name,omitempty"`
 }
 
+// DepartmentStat rolls up the users and PRs of a top-level team and every team below it.
+type DepartmentStat struct {
+	Department           string   `json:"department"`
+	Teams                []string `json:"teams"`
+	TotalUsers           int      `json:"total_users"`
+	ActiveUsers          int      `json:"active_users"`
+	TotalPRs             int      `json:"total_prs"`
+	OpenPRs              int      `json:"open_prs"`
+	AssignmentsCount     int      `json:"assignments_count"`
+	OpenAssignmentsCount int      `json:"open_assignments_count"`
+}
+
 type StatsSummary struct {
-	TotalUsers        int     `json:"total_users"`
-	TotalPRs          int     `json:"total_prs"`
-	TotalAssignments  int     `json:"total_assignments"`
-	AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr"`
-	MostActiveUser    string  `json:"most_active_user,omitempty"`
-	MostReviewedPR    string  `json:"most_reviewed_pr,omitempty"`
+	TotalUsers        int            `json:"total_users"`
+	TotalPRs          int            `json:"total_prs"`
+	PRsByStatus       map[string]int `json:"prs_by_status"`
+	TotalAssignments  int            `json:"total_assignments"`
+	AvgReviewersPerPR float64        `json:"avg_reviewers_per_pr"`
+	MostActiveUser    string         `json:"most_active_user,omitempty"`
+	MostReviewedPR    string         `json:"most_reviewed_pr,omitempty"`
 }
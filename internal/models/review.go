@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+	ReviewPending          = "PENDING"
+)
+
+type Review struct {
+	ID            int       `json:"id" db:"id"`
+	PullRequestID string    `json:"pull_request_id" db:"pr_id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	State         string    `json:"state" db:"state"`
+	Comment       string    `json:"comment,omitempty" db:"comment"`
+	SubmittedAt   time.Time `json:"submitted_at" db:"created_at"`
+}
+
+type ReviewerState struct {
+	UserID      string     `json:"user_id"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// StaleReview is a review assignment on an open PR that has outlived the SLA of the author's team
+// without the reviewer submitting a review.
+type StaleReview struct {
+	PullRequestID         string     `json:"pull_request_id"`
+	PullRequestName       string     `json:"pull_request_name"`
+	AuthorID              string     `json:"author_id"`
+	ReviewerID            string     `json:"reviewer_id"`
+	TeamName              string     `json:"team_name"`
+	AssignedAt            time.Time  `json:"assigned_at"`
+	DueAt                 time.Time  `json:"due_at"`
+	EscalateAt            *time.Time `json:"escalate_at,omitempty"`
+	RemindedAt            *time.Time `json:"reminded_at,omitempty"`
+	EscalationAttemptedAt *time.Time `json:"escalation_attempted_at,omitempty"`
+	EscalationDue         bool       `json:"escalation_due"`
+}
+
+// StaleReviewReport summarises one pass of the stale review worker.
+type StaleReviewReport struct {
+	Reminded            []StaleReview        `json:"reminded"`
+	Escalated           []ReassignedPRDetail `json:"escalated"`
+	FailedReassignments []FailedReassignment `json:"failed_reassignments,omitempty"`
+}
@@ -1,6 +1,33 @@
 package models
 
 type Team struct {
-	TeamName string       `json:"team_name" db:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName                string       `json:"team_name" db:"team_name"`
+	ParentTeam              string       `json:"parent_team,omitempty" db:"parent_team"`
+	InheritPolicy           bool         `json:"inherit_policy" db:"inherit_policy"`
+	ReviewerStrategy        string       `json:"reviewer_strategy,omitempty" db:"reviewer_strategy"`
+	MinReviewers            int          `json:"min_reviewers" db:"min_reviewers"`
+	MaxReviewers            int          `json:"max_reviewers" db:"max_reviewers"`
+	FallbackTeams           []string     `json:"fallback_teams"`
+	RequiredApprovals       int          `json:"required_approvals" db:"required_approvals"`
+	BlockOnChangesRequested bool         `json:"block_on_changes_requested" db:"block_on_changes_requested"`
+	RequireOwnerApproval    bool         `json:"require_owner_approval" db:"require_owner_approval"`
+	ReviewSLAMinutes        int          `json:"review_sla_minutes" db:"review_sla_minutes"`
+	ReviewEscalationMinutes int          `json:"review_escalation_minutes" db:"review_escalation_minutes"`
+	Members                 []TeamMember `json:"members"`
+	Ancestors               []string     `json:"ancestors,omitempty"`
+	SubTeams                []Team       `json:"sub_teams,omitempty"`
+	AggregatedMembers       []TeamMember `json:"aggregated_members,omitempty"`
+}
+
+// CopyPolicy takes over the reviewer and merge policy of another team, leaving the
+// identity, hierarchy and fallbacks of the team untouched.
+func (t *Team) CopyPolicy(from *Team) {
+	t.ReviewerStrategy = from.ReviewerStrategy
+	t.MinReviewers = from.MinReviewers
+	t.MaxReviewers = from.MaxReviewers
+	t.RequiredApprovals = from.RequiredApprovals
+	t.BlockOnChangesRequested = from.BlockOnChangesRequested
+	t.RequireOwnerApproval = from.RequireOwnerApproval
+	t.ReviewSLAMinutes = from.ReviewSLAMinutes
+	t.ReviewEscalationMinutes = from.ReviewEscalationMinutes
 }
@@ -1,14 +1,33 @@
 package models
 
 type User struct {
-	UserID   string `json:"user_id" db:"user_id"`
-	Username string `json:"username" db:"username"`
-	TeamName string `json:"team_name" db:"team_name"`
-	IsActive bool   `json:"is_active" db:"is_active"`
+	UserID         string `json:"user_id" db:"user_id"`
+	Username       string `json:"username" db:"username"`
+	TeamName       string `json:"team_name" db:"team_name"`
+	IsActive       bool   `json:"is_active" db:"is_active"`
+	ReviewWeight   int    `json:"review_weight,omitempty" db:"review_weight"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty" db:"max_open_reviews"`
 }
 
 type TeamMember struct {
-	UserID   string `json:"user_id" db:"user_id"`
-	Username string `json:"username" db:"username"`
-	IsActive bool   `json:"is_active" db:"is_active"`
+	UserID         string `json:"user_id" db:"user_id"`
+	Username       string `json:"username" db:"username"`
+	IsActive       bool   `json:"is_active" db:"is_active"`
+	ReviewWeight   int    `json:"review_weight,omitempty" db:"review_weight"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty" db:"max_open_reviews"`
+}
+
+type ReviewCandidate struct {
+	UserID         string
+	Weight         int
+	OpenReviews    int
+	MaxOpenReviews *int
+	// LastAssignmentID is the id of the latest assignment event that gave the candidate
+	// a review, 0 if there is none. Ids grow with every assignment, so it orders candidates
+	// by when they were last picked.
+	LastAssignmentID int64
+}
+
+func (c ReviewCandidate) AtReviewLimit() bool {
+	return c.MaxOpenReviews != nil && c.OpenReviews >= *c.MaxOpenReviews
 }
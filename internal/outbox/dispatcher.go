@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+// Sink receives every event stored in the outbox. Delivery is at least once: an event
+// is offered again if any sink failed, so sinks should tolerate duplicates by event ID.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event models.Event) error
+}
+
+// Dispatcher publishes outbox events to sinks in the order they were written. An event
+// waiting for a retry does not hold back the events written after it.
+type Dispatcher struct {
+	repo  repository.OutboxStore
+	cfg   config.OutboxConfig
+	sinks []Sink
+}
+
+func NewDispatcher(repo repository.OutboxStore, cfg config.OutboxConfig, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{repo: repo, cfg: cfg, sinks: sinks}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DispatchPending(ctx); err != nil {
+				slog.ErrorContext(ctx, "outbox dispatch failed", "error", err)
+			}
+		}
+	}
+}
+
+// DispatchPending publishes one batch and returns how many events were published.
+// Events are claimed with a lease in one short statement and results are written one
+// by one afterwards, so no transaction stays open while sinks talk to the network.
+// It stops at the first event a sink rejects: that event is retried with backoff,
+// or dead-lettered after MaxAttempts, and the rest of the batch is released at once.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	events, err := d.repo.ClaimOutboxEvents(ctx, now, now.Add(d.cfg.LeaseTimeout), d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for i := range events {
+		event := &events[i]
+		if err := d.publish(ctx, event.Event()); err != nil {
+			d.fail(event, err)
+			if err := d.repo.UpdateOutboxEventResult(ctx, event); err != nil {
+				return published, err
+			}
+			return published, d.release(ctx, events[i+1:])
+		}
+
+		publishedAt := time.Now().UTC()
+		event.Attempts++
+		event.LastError = ""
+		event.PublishedAt = &publishedAt
+		if err := d.repo.UpdateOutboxEventResult(ctx, event); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+func (d *Dispatcher) fail(event *models.OutboxEvent, err error) {
+	now := time.Now().UTC()
+	event.Attempts++
+	event.LastError = err.Error()
+
+	if d.cfg.MaxAttempts > 0 && event.Attempts >= d.cfg.MaxAttempts {
+		event.DeadLetteredAt = &now
+		slog.Error("outbox event dead-lettered", "event_id", event.ID, "event_type", event.Type,
+			"attempts", event.Attempts, "error", err)
+		return
+	}
+
+	event.NextAttemptAt = now.Add(Backoff(d.cfg, event.Attempts))
+	slog.Warn("outbox event not published", "event_id", event.ID, "event_type", event.Type,
+		"attempts", event.Attempts, "next_attempt_at", event.NextAttemptAt, "error", err)
+}
+
+// release makes claimed events due again without counting an attempt.
+func (d *Dispatcher) release(ctx context.Context, events []models.OutboxEvent) error {
+	now := time.Now().UTC()
+	for i := range events {
+		events[i].NextAttemptAt = now
+		if err := d.repo.UpdateOutboxEventResult(ctx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) publish(ctx context.Context, event models.Event) error {
+	for _, sink := range d.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s sink: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed
+// attempts: BaseBackoff doubled per attempt and capped at MaxBackoff.
+func Backoff(cfg config.OutboxConfig, attempts int) time.Duration {
+	delay := cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+	return delay
+}
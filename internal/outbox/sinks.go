@@ -0,0 +1,94 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// LogSink writes every event to the standard logger.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Publish(_ context.Context, event models.Event) error {
+	slog.Info("event", "event_id", event.ID, "event_type", event.Type, "team_name", event.TeamName, "data", event.Data)
+	return nil
+}
+
+// HTTPSink posts every event as JSON to a single endpoint. Any non-2xx response is a failure.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+func (s *HTTPSink) Publish(ctx context.Context, event models.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ChannelSink hands events to in-process consumers. Publish blocks while the buffer is full,
+// which holds back the dispatcher instead of dropping events.
+type ChannelSink struct {
+	events chan models.Event
+}
+
+func NewChannelSink(buffer int) *ChannelSink {
+	return &ChannelSink{events: make(chan models.Event, buffer)}
+}
+
+func (s *ChannelSink) Name() string {
+	return "channel"
+}
+
+func (s *ChannelSink) Events() <-chan models.Event {
+	return s.events
+}
+
+func (s *ChannelSink) Publish(ctx context.Context, event models.Event) error {
+	select {
+	case s.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+const absenceColumns = `id, user_id, starts_at, ends_at, reason, reassigned_at, created_at`
+
+func scanAbsence(row rowScanner) (*models.Absence, error) {
+	var absence models.Absence
+	var reason sql.NullString
+	var reassignedAt sql.NullTime
+	if err := row.Scan(&absence.ID, &absence.UserID, &absence.StartsAt, &absence.EndsAt, &reason,
+		&reassignedAt, &absence.CreatedAt); err != nil {
+		return nil, err
+	}
+	absence.Reason = reason.String
+	if reassignedAt.Valid {
+		absence.ReassignedAt = &reassignedAt.Time
+	}
+	return &absence, nil
+}
+
+func scanAbsences(rows *sql.Rows) ([]models.Absence, error) {
+	defer rows.Close()
+
+	absences := []models.Absence{}
+	for rows.Next() {
+		absence, err := scanAbsence(rows)
+		if err != nil {
+			return nil, err
+		}
+		absences = append(absences, *absence)
+	}
+	return absences, rows.Err()
+}
+
+func (r *Repository) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	query := `INSERT INTO user_absences (user_id, starts_at, ends_at, reason) 
+	          VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, absence.UserID, absence.StartsAt, absence.EndsAt, nullString(absence.Reason)).
+		Scan(&absence.ID, &absence.CreatedAt)
+}
+
+// GetAbsencesByUser returns current and upcoming absences, earliest first.
+func (r *Repository) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	query := `SELECT ` + absenceColumns + ` FROM user_absences 
+	          WHERE user_id = $1 AND ends_at > ` + r.dialect.now + ` ORDER BY starts_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAbsences(rows)
+}
+
+func (r *Repository) DeleteAbsence(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM user_absences WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// LockStartedAbsencesInTx returns absences that are in progress but whose reviews have not
+// been handed over yet. Rows locked by another scheduler are skipped.
+func (r *Repository) LockStartedAbsencesInTx(ctx context.Context, tx Tx, now time.Time, limit int) ([]models.Absence, error) {
+	query := `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE reassigned_at IS NULL AND starts_at <= $1 AND ends_at > $1
+		ORDER BY starts_at, id
+		LIMIT $2
+		` + r.dialect.forUpdateSkipLocked
+	rows, err := sqlTx(tx).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAbsences(rows)
+}
+
+func (r *Repository) MarkAbsenceReassignedInTx(ctx context.Context, tx Tx, id int64, at time.Time) error {
+	_, err := sqlTx(tx).ExecContext(ctx, `UPDATE user_absences SET reassigned_at = $1 WHERE id = $2`, at, id)
+	return err
+}
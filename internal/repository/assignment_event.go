@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// CreateAssignmentEventsInTx also queues a reviewer.* outbox event for every entry.
+func (r *Repository) CreateAssignmentEventsInTx(ctx context.Context, tx Tx, events []models.AssignmentEvent) error {
+	query := `INSERT INTO assignment_events (pr_id, action, user_id, previous_user_id, operation, reason) 
+	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	teams := make(map[string]string)
+	for _, event := range events {
+		err := sqlTx(tx).QueryRowContext(ctx, query, event.PullRequestID, event.Action, event.UserID,
+			nullString(event.PreviousUserID), event.Operation, nullString(event.Reason)).Scan(&event.ID, &event.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		teamName, ok := teams[event.PullRequestID]
+		if !ok {
+			teamName, err = prTeam(ctx, sqlTx(tx), event.PullRequestID)
+			if err != nil {
+				return err
+			}
+			teams[event.PullRequestID] = teamName
+		}
+
+		if err := r.insertOutboxEvent(ctx, sqlTx(tx), AssignmentEventType(event.Action), teamName, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAssignmentEvents filters by PR and by user; a user matches both as the
+// affected reviewer and as the reviewer that was replaced. Empty filters are ignored.
+func (r *Repository) GetAssignmentEvents(ctx context.Context, prID, userID string) ([]models.AssignmentEvent, error) {
+	query := `
+		SELECT id, pr_id, action, user_id, previous_user_id, operation, reason, created_at
+		FROM assignment_events
+		WHERE ($1 = '' OR pr_id = $1)
+		  AND ($2 = '' OR user_id = $2 OR previous_user_id = $2)
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, prID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AssignmentEvent{}
+	for rows.Next() {
+		var event models.AssignmentEvent
+		var previousUserID, reason sql.NullString
+		if err := rows.Scan(&event.ID, &event.PullRequestID, &event.Action, &event.UserID,
+			&previousUserID, &event.Operation, &reason, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.PreviousUserID = previousUserID.String
+		event.Reason = reason.String
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// dialect holds the SQL that differs between the databases Repository runs on.
+// Everything else is written once in the subset Postgres and SQLite share.
+type dialect struct {
+	// now is the current time in the form the TIMESTAMP columns hold it.
+	now string
+	// forUpdate locks the rows a SELECT returns until the transaction ends.
+	forUpdate string
+	// forUpdateSkipLocked locks the rows as well, skipping rows another transaction holds.
+	forUpdateSkipLocked string
+	// addMinutes returns an expression for the timestamp moved the given minutes ahead.
+	addMinutes func(timestamp, minutes string) string
+	// isUniqueViolation reports whether err is the driver error for a duplicate unique key.
+	isUniqueViolation func(err error) bool
+}
+
+var postgresDialect = dialect{
+	now:                 "LOCALTIMESTAMP",
+	forUpdate:           "FOR UPDATE",
+	forUpdateSkipLocked: "FOR UPDATE SKIP LOCKED",
+	addMinutes: func(timestamp, minutes string) string {
+		return timestamp + " + make_interval(mins => " + minutes + ")"
+	},
+	isUniqueViolation: func(err error) bool {
+		var pqErr *pq.Error
+		return errors.As(err, &pqErr) && pqErr.Code == "23505"
+	},
+}
+
+// sqliteDialect locks nothing row by row: every transaction starts with BEGIN IMMEDIATE
+// and holds the database write lock until it ends, see config.DatabaseConfig.SQLiteDSN.
+var sqliteDialect = dialect{
+	now: "strftime('%Y-%m-%d %H:%M:%f', 'now')",
+	addMinutes: func(timestamp, minutes string) string {
+		return "strftime('%Y-%m-%d %H:%M:%f', " + timestamp + ", '+' || " + minutes + " || ' minutes')"
+	},
+	isUniqueViolation: func(err error) bool {
+		var sqliteErr *sqlite.Error
+		return errors.As(err, &sqliteErr) &&
+			(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+	},
+}
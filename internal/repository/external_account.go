@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (r *Repository) SetExternalAccount(ctx context.Context, account *models.ExternalAccount) error {
+	query := `INSERT INTO external_accounts (provider, login, user_id) VALUES ($1, $2, $3)
+	          ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id
+	          RETURNING created_at`
+	return r.db.QueryRowContext(ctx, query, account.Provider, account.Login, account.UserID).Scan(&account.CreatedAt)
+}
+
+func (r *Repository) GetExternalAccountUserID(ctx context.Context, provider, login string) (string, error) {
+	var userID string
+	query := `SELECT user_id FROM external_accounts WHERE provider = $1 AND login = $2`
+	err := r.db.QueryRowContext(ctx, query, provider, login).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return userID, err
+}
+
+func (r *Repository) GetExternalAccounts(ctx context.Context, provider string) ([]models.ExternalAccount, error) {
+	query := `SELECT provider, login, user_id, created_at FROM external_accounts WHERE provider = $1 ORDER BY login`
+	rows, err := r.db.QueryContext(ctx, query, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.ExternalAccount{}
+	for rows.Next() {
+		var account models.ExternalAccount
+		if err := rows.Scan(&account.Provider, &account.Login, &account.UserID, &account.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *Repository) DeleteExternalAccount(ctx context.Context, provider, login string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM external_accounts WHERE provider = $1 AND login = $2`, provider, login)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
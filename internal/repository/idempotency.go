@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// ClaimIdempotencyKey stores key as pending unless a live record for the same key and
+// endpoint exists, and returns that record, or nil if the caller now owns the key.
+// Expired records are removed on the way.
+func (r *Repository) ClaimIdempotencyKey(ctx context.Context, key *models.IdempotencyKey, now time.Time) (*models.IdempotencyKey, error) {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now); err != nil {
+		return nil, err
+	}
+
+	for {
+		query := `INSERT INTO idempotency_keys (idempotency_key, endpoint, request_hash, expires_at)
+		          VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`
+		result, err := r.db.ExecContext(ctx, query, key.Key, key.Endpoint, key.RequestHash, key.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if inserted > 0 {
+			return nil, nil
+		}
+
+		existing, err := r.getIdempotencyKey(ctx, key.Key, key.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		// A record released between the insert and the read is claimed on the next try.
+		if existing != nil {
+			return existing, nil
+		}
+	}
+}
+
+func (r *Repository) getIdempotencyKey(ctx context.Context, key, endpoint string) (*models.IdempotencyKey, error) {
+	query := `SELECT idempotency_key, endpoint, request_hash, status_code, response_body, expires_at
+	          FROM idempotency_keys WHERE idempotency_key = $1 AND endpoint = $2`
+	var record models.IdempotencyKey
+	var statusCode sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, key, endpoint).Scan(&record.Key, &record.Endpoint,
+		&record.RequestHash, &statusCode, &record.Response, &record.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	record.StatusCode = int(statusCode.Int64)
+	return &record, nil
+}
+
+func (r *Repository) CompleteIdempotencyKey(ctx context.Context, key, endpoint string, statusCode int, response []byte) error {
+	query := `UPDATE idempotency_keys SET status_code = $1, response_body = $2
+	          WHERE idempotency_key = $3 AND endpoint = $4`
+	_, err := r.db.ExecContext(ctx, query, statusCode, response, key, endpoint)
+	return err
+}
+
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, key, endpoint string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND endpoint = $2`, key, endpoint)
+	return err
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Store) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.users[absence.UserID]; !ok {
+			return constraintError("user %q does not exist", absence.UserID)
+		}
+		if !absence.EndsAt.After(absence.StartsAt) {
+			return constraintError("absence must end after it starts")
+		}
+
+		st.seq.absence++
+		absence.ID = st.seq.absence
+		absence.CreatedAt = now()
+
+		row := *absence
+		row.ReassignedAt = nil
+		st.absences = append(st.absences, row)
+		return nil
+	})
+}
+
+// GetAbsencesByUser returns current and upcoming absences, earliest first.
+func (s *Store) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	at := now()
+	absences := []models.Absence{}
+	for _, absence := range st.absences {
+		if absence.UserID == userID && absence.EndsAt.After(at) {
+			absences = append(absences, absence)
+		}
+	}
+	sortAbsences(absences)
+	return absences, nil
+}
+
+func (s *Store) DeleteAbsence(ctx context.Context, id int64) (bool, error) {
+	deleted := false
+	err := s.write(ctx, func(st *state) error {
+		if i := st.absence(id); i >= 0 {
+			st.absences = slices.Delete(st.absences, i, i+1)
+			deleted = true
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// LockStartedAbsencesInTx returns absences that are in progress but whose reviews have not
+// been handed over yet. As with outbox events, rows are not locked.
+func (s *Store) LockStartedAbsencesInTx(ctx context.Context, rtx repository.Tx, now time.Time, limit int) ([]models.Absence, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+
+	absences := []models.Absence{}
+	for _, absence := range st.absences {
+		if absence.ReassignedAt == nil && absence.ActiveAt(now) {
+			absences = append(absences, absence)
+		}
+	}
+	sortAbsences(absences)
+	if len(absences) > limit {
+		absences = absences[:limit]
+	}
+	return absences, nil
+}
+
+func (s *Store) MarkAbsenceReassignedInTx(ctx context.Context, rtx repository.Tx, id int64, at time.Time) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	if i := st.absence(id); i >= 0 {
+		st.absences[i].ReassignedAt = &at
+	}
+	return nil
+}
+
+func (st *state) absence(id int64) int {
+	return slices.IndexFunc(st.absences, func(absence models.Absence) bool { return absence.ID == id })
+}
+
+func sortAbsences(absences []models.Absence) {
+	sort.Slice(absences, func(i, j int) bool {
+		if !absences[i].StartsAt.Equal(absences[j].StartsAt) {
+			return absences[i].StartsAt.Before(absences[j].StartsAt)
+		}
+		return absences[i].ID < absences[j].ID
+	})
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+// CreateAssignmentEventsInTx also queues a reviewer.* outbox event for every entry.
+func (s *Store) CreateAssignmentEventsInTx(ctx context.Context, rtx repository.Tx, events []models.AssignmentEvent) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if _, ok := st.prs[event.PullRequestID]; !ok {
+			return constraintError("pull request %q does not exist", event.PullRequestID)
+		}
+
+		st.seq.assignment++
+		event.ID = st.seq.assignment
+		event.CreatedAt = now()
+		st.assignments = append(st.assignments, event)
+
+		if err := st.insertOutboxEvent(repository.AssignmentEventType(event.Action), st.prTeam(event.PullRequestID), event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAssignmentEvents filters by PR and by user; a user matches both as the affected
+// reviewer and as the reviewer that was replaced. Empty filters are ignored.
+func (s *Store) GetAssignmentEvents(ctx context.Context, prID, userID string) ([]models.AssignmentEvent, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []models.AssignmentEvent{}
+	for _, event := range st.assignments {
+		if prID != "" && event.PullRequestID != prID {
+			continue
+		}
+		if userID != "" && event.UserID != userID && event.PreviousUserID != userID {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// SetExternalAccount links the login to the user, keeping the creation time of an existing link.
+func (s *Store) SetExternalAccount(ctx context.Context, account *models.ExternalAccount) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.users[account.UserID]; !ok {
+			return constraintError("user %q does not exist", account.UserID)
+		}
+
+		i := st.externalAccount(account.Provider, account.Login)
+		if i >= 0 {
+			st.accounts[i].UserID = account.UserID
+			account.CreatedAt = st.accounts[i].CreatedAt
+			return nil
+		}
+
+		account.CreatedAt = now()
+		st.accounts = append(st.accounts, *account)
+		return nil
+	})
+}
+
+func (st *state) externalAccount(provider, login string) int {
+	return slices.IndexFunc(st.accounts, func(account models.ExternalAccount) bool {
+		return account.Provider == provider && account.Login == login
+	})
+}
+
+func (s *Store) GetExternalAccountUserID(ctx context.Context, provider, login string) (string, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return "", err
+	}
+	if i := st.externalAccount(provider, login); i >= 0 {
+		return st.accounts[i].UserID, nil
+	}
+	return "", nil
+}
+
+func (s *Store) GetExternalAccounts(ctx context.Context, provider string) ([]models.ExternalAccount, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []models.ExternalAccount{}
+	for _, account := range st.accounts {
+		if account.Provider == provider {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Login < accounts[j].Login })
+	return accounts, nil
+}
+
+func (s *Store) DeleteExternalAccount(ctx context.Context, provider, login string) (bool, error) {
+	deleted := false
+	err := s.write(ctx, func(st *state) error {
+		if i := st.externalAccount(provider, login); i >= 0 {
+			st.accounts = slices.Delete(st.accounts, i, i+1)
+			deleted = true
+		}
+		return nil
+	})
+	return deleted, err
+}
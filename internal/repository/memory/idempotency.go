@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+type idempotencyKeyID struct {
+	key      string
+	endpoint string
+}
+
+// ClaimIdempotencyKey stores key as pending unless a live record for the same key and
+// endpoint exists, and returns that record, or nil if the caller now owns the key.
+// Expired records are removed on the way.
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, key *models.IdempotencyKey, now time.Time) (*models.IdempotencyKey, error) {
+	var existing *models.IdempotencyKey
+	err := s.write(ctx, func(st *state) error {
+		maps.DeleteFunc(st.idempotency, func(_ idempotencyKeyID, record models.IdempotencyKey) bool {
+			return !record.ExpiresAt.After(now)
+		})
+
+		id := idempotencyKeyID{key: key.Key, endpoint: key.Endpoint}
+		if record, ok := st.idempotency[id]; ok {
+			record.Response = slices.Clone(record.Response)
+			existing = &record
+			return nil
+		}
+		st.idempotency[id] = models.IdempotencyKey{
+			Key:         key.Key,
+			Endpoint:    key.Endpoint,
+			RequestHash: key.RequestHash,
+			ExpiresAt:   key.ExpiresAt,
+		}
+		return nil
+	})
+	return existing, err
+}
+
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, key, endpoint string, statusCode int, response []byte) error {
+	return s.write(ctx, func(st *state) error {
+		id := idempotencyKeyID{key: key, endpoint: endpoint}
+		record, ok := st.idempotency[id]
+		if !ok {
+			return nil
+		}
+		record.StatusCode = statusCode
+		record.Response = slices.Clone(response)
+		st.idempotency[id] = record
+		return nil
+	})
+}
+
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, key, endpoint string) error {
+	return s.write(ctx, func(st *state) error {
+		delete(st.idempotency, idempotencyKeyID{key: key, endpoint: endpoint})
+		return nil
+	})
+}
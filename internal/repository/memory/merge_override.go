@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Store) CreateMergeOverrideInTx(ctx context.Context, rtx repository.Tx, override *models.MergeOverride) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	if _, ok := st.prs[override.PullRequestID]; !ok {
+		return constraintError("pull request %q does not exist", override.PullRequestID)
+	}
+
+	st.seq.override++
+	override.ID = int(st.seq.override)
+	override.CreatedAt = now()
+
+	row := *override
+	row.UnmetRules = append([]string{}, override.UnmetRules...)
+	st.overrides = append(st.overrides, row)
+	return nil
+}
+
+func (s *Store) GetMergeOverrides(ctx context.Context, prID string) ([]models.MergeOverride, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := []models.MergeOverride{}
+	for _, override := range st.overrides {
+		if override.PullRequestID == prID {
+			override.UnmetRules = slices.Clone(override.UnmetRules)
+			overrides = append(overrides, override)
+		}
+	}
+	return overrides, nil
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (st *state) insertOutboxEvent(eventType, teamName string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	at := now()
+	st.seq.outbox++
+	st.outbox = append(st.outbox, models.OutboxEvent{
+		ID:         st.seq.outbox,
+		Type:       eventType,
+		TeamName:   teamName,
+		Data:       payload,
+		OccurredAt: at,
+
+		NextAttemptAt: at,
+	})
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit due events in insertion order and pushes their
+// next_attempt_at to leaseUntil.
+func (s *Store) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	events := []models.OutboxEvent{}
+	err := s.write(ctx, func(st *state) error {
+		outbox := slices.Clone(st.outbox)
+		for i := range outbox {
+			if len(events) >= limit {
+				break
+			}
+			event := &outbox[i]
+			if event.PublishedAt != nil || event.DeadLetteredAt != nil || event.NextAttemptAt.After(now) {
+				continue
+			}
+			event.NextAttemptAt = leaseUntil
+			events = append(events, *event)
+		}
+		st.outbox = outbox
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Store) UpdateOutboxEventResult(ctx context.Context, event *models.OutboxEvent) error {
+	return s.write(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.outbox, func(row models.OutboxEvent) bool { return row.ID == event.ID })
+		if i < 0 {
+			return nil
+		}
+		outbox := slices.Clone(st.outbox)
+		outbox[i].Attempts = event.Attempts
+		outbox[i].LastError = event.LastError
+		outbox[i].NextAttemptAt = event.NextAttemptAt
+		outbox[i].PublishedAt = event.PublishedAt
+		outbox[i].DeadLetteredAt = event.DeadLetteredAt
+		st.outbox = outbox
+		return nil
+	})
+}
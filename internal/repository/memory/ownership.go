@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Store) ReplaceOwnershipRules(ctx context.Context, rules []models.OwnershipRule) error {
+	return s.write(ctx, func(st *state) error {
+		replaced := make([]models.OwnershipRule, 0, len(rules))
+		for _, rule := range rules {
+			if _, ok := st.users[rule.OwnerUserID]; rule.OwnerUserID != "" && !ok {
+				return constraintError("owner user %q does not exist", rule.OwnerUserID)
+			}
+			if _, ok := st.teams[rule.OwnerTeamName]; rule.OwnerTeamName != "" && !ok {
+				return constraintError("owner team %q does not exist", rule.OwnerTeamName)
+			}
+			st.seq.rule++
+			rule.ID = int(st.seq.rule)
+			replaced = append(replaced, rule)
+		}
+		st.rules = replaced
+		return nil
+	})
+}
+
+func (s *Store) GetOwnershipRules(ctx context.Context) ([]models.OwnershipRule, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := append([]models.OwnershipRule{}, st.rules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Position != rules[j].Position {
+			return rules[i].Position < rules[j].Position
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules, nil
+}
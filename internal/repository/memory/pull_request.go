@@ -0,0 +1,345 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Store) CreatePR(ctx context.Context, pr *models.PullRequest) error {
+	return s.write(ctx, func(st *state) error {
+		return st.createPR(pr)
+	})
+}
+
+func (s *Store) CreatePRInTx(ctx context.Context, rtx repository.Tx, pr *models.PullRequest) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.createPR(pr)
+}
+
+func (st *state) createPR(pr *models.PullRequest) error {
+	if err := st.insertPR(pr); err != nil {
+		return err
+	}
+	return st.insertOutboxEvent(models.EventPRCreated, st.users[pr.AuthorID].TeamName, pr)
+}
+
+// InsertPR stores the PR with its reviewers and files but queues no outbox event, like rows
+// written straight into the Postgres tables. Tests use it for fixtures.
+func (s *Store) InsertPR(ctx context.Context, pr *models.PullRequest) error {
+	return s.write(ctx, func(st *state) error {
+		return st.insertPR(pr)
+	})
+}
+
+func (st *state) insertPR(pr *models.PullRequest) error {
+	if _, ok := st.prs[pr.PullRequestID]; ok {
+		return constraintError("pull request %q already exists", pr.PullRequestID)
+	}
+	if _, ok := st.users[pr.AuthorID]; !ok {
+		return constraintError("author %q does not exist", pr.AuthorID)
+	}
+
+	row := models.PullRequest{
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		AuthorID:        pr.AuthorID,
+		Status:          pr.Status,
+		CreatedAt:       now(),
+	}
+	st.prs[pr.PullRequestID] = prRow{PullRequest: row, seq: st.nextRowSeq()}
+
+	if err := st.insertReviewers(pr.PullRequestID, pr.AssignedReviewers, pr.ReviewerFallbackTeams); err != nil {
+		return err
+	}
+
+	var files []string
+	for _, path := range pr.ChangedFiles {
+		if !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	if len(files) > 0 {
+		st.files[pr.PullRequestID] = files
+	}
+	return nil
+}
+
+func (st *state) insertReviewers(prID string, reviewerIDs []string, fallbackTeams map[string]string) error {
+	reviewers := slices.Clone(st.reviewers[prID])
+	assignedAt := now()
+	for _, reviewerID := range reviewerIDs {
+		if _, ok := st.users[reviewerID]; !ok {
+			return constraintError("reviewer %q does not exist", reviewerID)
+		}
+		if slices.ContainsFunc(reviewers, func(r reviewerRow) bool { return r.UserID == reviewerID }) {
+			return constraintError("reviewer %q is already assigned to %q", reviewerID, prID)
+		}
+		reviewers = append(reviewers, reviewerRow{
+			UserID:       reviewerID,
+			FallbackTeam: fallbackTeams[reviewerID],
+			AssignedAt:   assignedAt,
+		})
+	}
+	st.reviewers[prID] = reviewers
+	return nil
+}
+
+func (s *Store) GetPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.pr(prID), nil
+}
+
+func (s *Store) GetPRInTx(ctx context.Context, rtx repository.Tx, prID string) (*models.PullRequest, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	return st.pr(prID), nil
+}
+
+func (st *state) pr(prID string) *models.PullRequest {
+	row, ok := st.prs[prID]
+	if !ok {
+		return nil
+	}
+	pr := row.PullRequest
+
+	pr.AssignedReviewers = st.prReviewers(prID)
+	pr.FallbackReviewers = st.prFallbackReviewers(prID)
+	if files := st.files[prID]; len(files) > 0 {
+		pr.ChangedFiles = slices.Sorted(slices.Values(files))
+	}
+
+	latest := st.reviewerStates(prID)
+	pr.ReviewerStates = make([]models.ReviewerState, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		state, ok := latest[reviewerID]
+		if !ok {
+			state = models.ReviewerState{UserID: reviewerID, State: models.ReviewPending}
+		}
+		pr.ReviewerStates = append(pr.ReviewerStates, state)
+	}
+
+	return &pr
+}
+
+// prReviewers returns reviewer IDs ordered by assigned_at, user_id, or nil if there are none.
+func (st *state) prReviewers(prID string) []string {
+	return st.prReviewersWhere(prID, func(reviewerRow) bool { return true })
+}
+
+// prFallbackReviewers returns reviewers that were drawn from another team when they were assigned.
+func (st *state) prFallbackReviewers(prID string) []string {
+	return st.prReviewersWhere(prID, func(row reviewerRow) bool { return row.FallbackTeam != "" })
+}
+
+func (st *state) prReviewersWhere(prID string, match func(row reviewerRow) bool) []string {
+	rows := slices.Clone(st.reviewers[prID])
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].AssignedAt.Equal(rows[j].AssignedAt) {
+			return rows[i].AssignedAt.Before(rows[j].AssignedAt)
+		}
+		return rows[i].UserID < rows[j].UserID
+	})
+
+	var reviewers []string
+	for _, row := range rows {
+		if match(row) {
+			reviewers = append(reviewers, row.UserID)
+		}
+	}
+	return reviewers
+}
+
+func (s *Store) GetPRReviewers(ctx context.Context, prID string) ([]string, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.prReviewers(prID), nil
+}
+
+func (s *Store) GetPRReviewersInTx(ctx context.Context, rtx repository.Tx, prID string) ([]string, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	return st.prReviewers(prID), nil
+}
+
+// LockPRInTx takes the writer slot, which serializes the transaction with every other
+// writer, and returns the PR status or an empty status if the PR does not exist.
+func (s *Store) LockPRInTx(ctx context.Context, rtx repository.Tx, prID string) (string, error) {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return "", err
+	}
+	return st.prs[prID].Status, nil
+}
+
+func (s *Store) UpdatePRStatusInTx(ctx context.Context, rtx repository.Tx, prID, status string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.updatePRStatus(prID, status)
+}
+
+func (st *state) updatePRStatus(prID, status string) error {
+	row, ok := st.prs[prID]
+	if !ok {
+		return nil
+	}
+
+	row.Status = status
+	row.MergedAt, row.ClosedAt = nil, nil
+	at := now().Truncate(time.Second)
+	switch status {
+	case models.PRStatusMerged:
+		row.MergedAt = &at
+	case models.PRStatusClosed:
+		row.ClosedAt = &at
+	}
+	st.prs[prID] = row
+
+	eventType := repository.PRStatusEventType(status)
+	if eventType == "" {
+		return nil
+	}
+
+	pr := models.PullRequestShort{
+		PullRequestID:   row.PullRequestID,
+		PullRequestName: row.PullRequestName,
+		AuthorID:        row.AuthorID,
+		Status:          row.Status,
+	}
+	return st.insertOutboxEvent(eventType, st.prTeam(prID), pr)
+}
+
+// prTeam returns the team of the PR author, which is the team that receives events about the PR.
+func (st *state) prTeam(prID string) string {
+	return st.users[st.prs[prID].AuthorID].TeamName
+}
+
+func (s *Store) PRExists(ctx context.Context, prID string) (bool, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := st.prs[prID]
+	return ok, nil
+}
+
+// UpdatePRReviewersInTx applies only the difference, so reviewers that stay on the PR keep
+// their original assigned_at and fallback team.
+func (s *Store) UpdatePRReviewersInTx(ctx context.Context, rtx repository.Tx, prID string, reviewers []string, fallbackTeams map[string]string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+
+	current := st.reviewers[prID]
+	kept := make([]reviewerRow, 0, len(reviewers))
+	for _, row := range current {
+		if slices.Contains(reviewers, row.UserID) {
+			kept = append(kept, row)
+		}
+	}
+	st.reviewers[prID] = kept
+
+	var added []string
+	for _, reviewerID := range reviewers {
+		if !slices.ContainsFunc(current, func(r reviewerRow) bool { return r.UserID == reviewerID }) {
+			added = append(added, reviewerID)
+		}
+	}
+	return st.insertReviewers(prID, added, fallbackTeams)
+}
+
+func (s *Store) GetPRsByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []prRow
+	for prID, reviewers := range st.reviewers {
+		row := st.prs[prID]
+		if row.Status == models.PRStatusClosed {
+			continue
+		}
+		if slices.ContainsFunc(reviewers, func(r reviewerRow) bool { return r.UserID == userID }) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].CreatedAt.Equal(rows[j].CreatedAt) {
+			return rows[i].CreatedAt.After(rows[j].CreatedAt)
+		}
+		return rows[i].seq > rows[j].seq
+	})
+
+	var prs []models.PullRequestShort
+	for _, row := range rows {
+		prs = append(prs, models.PullRequestShort{
+			PullRequestID:   row.PullRequestID,
+			PullRequestName: row.PullRequestName,
+			AuthorID:        row.AuthorID,
+			Status:          row.Status,
+		})
+	}
+	return prs, nil
+}
+
+func (s *Store) GetAllOpenPRs(ctx context.Context) ([]*models.PullRequest, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []*models.PullRequest
+	for _, row := range st.prsBySeq() {
+		if row.Status != models.PRStatusOpen {
+			continue
+		}
+		pr := row.PullRequest
+		pr.AssignedReviewers = st.prReviewers(pr.PullRequestID)
+		prs = append(prs, &pr)
+	}
+	return prs, nil
+}
+
+func (st *state) prsBySeq() []prRow {
+	rows := make([]prRow, 0, len(st.prs))
+	for _, row := range st.prs {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq < rows[j].seq })
+	return rows
+}
+
+// SetAssignedAt moves the assignment of a reviewer back or forth in time. Tests use it
+// to age assignments past a review SLA.
+func (s *Store) SetAssignedAt(prID, userID string, at time.Time) {
+	_ = s.write(context.Background(), func(st *state) error {
+		reviewers := slices.Clone(st.reviewers[prID])
+		for i := range reviewers {
+			if reviewers[i].UserID == userID {
+				reviewers[i].AssignedAt = at.UTC()
+			}
+		}
+		st.reviewers[prID] = reviewers
+		return nil
+	})
+}
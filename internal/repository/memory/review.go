@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Store) CreateReviewInTx(ctx context.Context, rtx repository.Tx, review *models.Review) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	if _, ok := st.prs[review.PullRequestID]; !ok {
+		return constraintError("pull request %q does not exist", review.PullRequestID)
+	}
+	if _, ok := st.users[review.UserID]; !ok {
+		return constraintError("user %q does not exist", review.UserID)
+	}
+
+	st.seq.review++
+	review.ID = int(st.seq.review)
+	review.SubmittedAt = now()
+	st.reviews = append(st.reviews, *review)
+	return nil
+}
+
+// GetPRReviews returns reviews in submission order; reviews are stored that way already.
+func (s *Store) GetPRReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := []models.Review{}
+	for _, review := range st.reviews {
+		if review.PullRequestID == prID {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+// reviewerStates returns the latest review of each current reviewer submitted since they
+// were assigned.
+func (st *state) reviewerStates(prID string) map[string]models.ReviewerState {
+	assignedAt := make(map[string]time.Time)
+	for _, row := range st.reviewers[prID] {
+		assignedAt[row.UserID] = row.AssignedAt
+	}
+
+	states := make(map[string]models.ReviewerState)
+	for _, review := range st.reviews {
+		since, ok := assignedAt[review.UserID]
+		if review.PullRequestID != prID || !ok || review.SubmittedAt.Before(since) {
+			continue
+		}
+		submittedAt := review.SubmittedAt
+		states[review.UserID] = models.ReviewerState{UserID: review.UserID, State: review.State, SubmittedAt: &submittedAt}
+	}
+	return states
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// GetStaleReviews mirrors Repository.GetStaleReviews: reviewers on open PRs whose assignment
+// is older than the effective SLA of the author's team and who have not reviewed since.
+func (s *Store) GetStaleReviews(ctx context.Context, teamName string) ([]models.StaleReview, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	at := now()
+	reviews := []models.StaleReview{}
+	for prID, reviewers := range st.reviewers {
+		pr := st.prs[prID]
+		if pr.Status != models.PRStatusOpen {
+			continue
+		}
+		team := st.teamSettings(st.users[pr.AuthorID].TeamName)
+		if team == nil || team.ReviewSLAMinutes <= 0 || (teamName != "" && team.TeamName != teamName) {
+			continue
+		}
+
+		for _, reviewer := range reviewers {
+			dueAt := reviewer.AssignedAt.Add(time.Duration(team.ReviewSLAMinutes) * time.Minute)
+			if dueAt.After(at) || st.reviewedSince(prID, reviewer.UserID, reviewer.AssignedAt) {
+				continue
+			}
+
+			review := models.StaleReview{
+				PullRequestID:         prID,
+				PullRequestName:       pr.PullRequestName,
+				AuthorID:              pr.AuthorID,
+				ReviewerID:            reviewer.UserID,
+				TeamName:              team.TeamName,
+				AssignedAt:            reviewer.AssignedAt,
+				DueAt:                 dueAt,
+				RemindedAt:            reviewer.RemindedAt,
+				EscalationAttemptedAt: reviewer.EscalationAttemptedAt,
+			}
+			if team.ReviewEscalationMinutes > 0 {
+				escalateAt := reviewer.AssignedAt.Add(time.Duration(team.ReviewEscalationMinutes) * time.Minute)
+				review.EscalateAt = &escalateAt
+				review.EscalationDue = !escalateAt.After(at)
+				if attemptedAt := reviewer.EscalationAttemptedAt; attemptedAt != nil {
+					retryAt := attemptedAt.Add(time.Duration(team.ReviewSLAMinutes) * time.Minute)
+					review.EscalationDue = review.EscalationDue && !retryAt.After(at)
+				}
+			}
+			reviews = append(reviews, review)
+		}
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		a, b := reviews[i], reviews[j]
+		if !a.DueAt.Equal(b.DueAt) {
+			return a.DueAt.Before(b.DueAt)
+		}
+		if a.PullRequestID != b.PullRequestID {
+			return a.PullRequestID < b.PullRequestID
+		}
+		return a.ReviewerID < b.ReviewerID
+	})
+	return reviews, nil
+}
+
+func (st *state) reviewedSince(prID, userID string, since time.Time) bool {
+	return slices.ContainsFunc(st.reviews, func(review models.Review) bool {
+		return review.PullRequestID == prID && review.UserID == userID && !review.SubmittedAt.Before(since)
+	})
+}
+
+// RecordReviewReminder marks the assignment as reminded and queues a review.reminder outbox
+// event. It reports false if a reminder was already sent.
+func (s *Store) RecordReviewReminder(ctx context.Context, review *models.StaleReview) (bool, error) {
+	recorded := false
+	err := s.write(ctx, func(st *state) error {
+		reviewers := slices.Clone(st.reviewers[review.PullRequestID])
+		i := slices.IndexFunc(reviewers, func(r reviewerRow) bool { return r.UserID == review.ReviewerID })
+		if i < 0 || reviewers[i].RemindedAt != nil {
+			return nil
+		}
+
+		remindedAt := now()
+		reviewers[i].RemindedAt = &remindedAt
+		st.reviewers[review.PullRequestID] = reviewers
+		review.RemindedAt = &remindedAt
+
+		recorded = true
+		return st.insertOutboxEvent(models.EventReviewReminder, review.TeamName, review)
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
+
+// RecordEscalationAttempt notes that the assignment could not be escalated, so the next attempt
+// is due one SLA period later.
+func (s *Store) RecordEscalationAttempt(ctx context.Context, review *models.StaleReview) error {
+	return s.write(ctx, func(st *state) error {
+		reviewers := slices.Clone(st.reviewers[review.PullRequestID])
+		i := slices.IndexFunc(reviewers, func(r reviewerRow) bool { return r.UserID == review.ReviewerID })
+		if i < 0 {
+			return nil
+		}
+
+		attemptedAt := now()
+		reviewers[i].EscalationAttemptedAt = &attemptedAt
+		st.reviewers[review.PullRequestID] = reviewers
+		review.EscalationAttemptedAt = &attemptedAt
+		return nil
+	})
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Store) GetUserAssignmentStats(ctx context.Context) ([]models.UserStat, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, openAssignments := st.assignmentCounts()
+	var stats []models.UserStat
+	for _, row := range st.users {
+		stats = append(stats, models.UserStat{
+			UserID:               row.UserID,
+			Username:             row.Username,
+			TeamName:             row.TeamName,
+			IsActive:             row.IsActive,
+			AssignmentsCount:     assignments[row.UserID],
+			OpenAssignmentsCount: openAssignments[row.UserID],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].AssignmentsCount != stats[j].AssignmentsCount {
+			return stats[i].AssignmentsCount > stats[j].AssignmentsCount
+		}
+		return stats[i].UserID < stats[j].UserID
+	})
+	return stats, nil
+}
+
+// assignmentCounts returns the number of assignments per reviewer, overall and on open PRs.
+func (st *state) assignmentCounts() (map[string]int, map[string]int) {
+	assignments := make(map[string]int)
+	openAssignments := make(map[string]int)
+	for prID, reviewers := range st.reviewers {
+		open := st.prs[prID].Status == models.PRStatusOpen
+		for _, reviewer := range reviewers {
+			assignments[reviewer.UserID]++
+			if open {
+				openAssignments[reviewer.UserID]++
+			}
+		}
+	}
+	return assignments, openAssignments
+}
+
+func (s *Store) GetPRAssignmentStats(ctx context.Context) ([]models.PRStat, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []models.PRStat
+	for _, row := range st.prs {
+		stats = append(stats, models.PRStat{
+			PullRequestID:   row.PullRequestID,
+			PullRequestName: row.PullRequestName,
+			AuthorID:        row.AuthorID,
+			Status:          row.Status,
+			ReviewersCount:  len(st.reviewers[row.PullRequestID]),
+			TeamName:        st.prTeam(row.PullRequestID),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].ReviewersCount != stats[j].ReviewersCount {
+			return stats[i].ReviewersCount > stats[j].ReviewersCount
+		}
+		return stats[i].PullRequestID < stats[j].PullRequestID
+	})
+	return stats, nil
+}
+
+func (s *Store) GetStatsSummary(ctx context.Context) (models.StatsSummary, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return models.StatsSummary{}, err
+	}
+
+	summary := models.StatsSummary{
+		TotalUsers: len(st.users),
+		TotalPRs:   len(st.prs),
+		PRsByStatus: map[string]int{
+			models.PRStatusDraft:  0,
+			models.PRStatusOpen:   0,
+			models.PRStatusMerged: 0,
+			models.PRStatusClosed: 0,
+		},
+	}
+	for _, row := range st.prs {
+		summary.PRsByStatus[row.Status]++
+	}
+
+	assignments, _ := st.assignmentCounts()
+	for _, count := range assignments {
+		summary.TotalAssignments += count
+	}
+
+	reviewablePRs := summary.TotalPRs - summary.PRsByStatus[models.PRStatusDraft]
+	if reviewablePRs > 0 {
+		summary.AvgReviewersPerPR = float64(summary.TotalAssignments) / float64(reviewablePRs)
+	}
+
+	summary.MostActiveUser = mostCounted(assignments)
+
+	reviewersPerPR := make(map[string]int)
+	for prID, reviewers := range st.reviewers {
+		if len(reviewers) > 0 {
+			reviewersPerPR[prID] = len(reviewers)
+		}
+	}
+	summary.MostReviewedPR = mostCounted(reviewersPerPR)
+
+	return summary, nil
+}
+
+// mostCounted returns the key with the highest count, breaking ties by the smallest key.
+func mostCounted(counts map[string]int) string {
+	best, bestCount := "", 0
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best, bestCount = key, count
+		}
+	}
+	return best
+}
@@ -0,0 +1,269 @@
+// Package memory keeps the whole data layer in process. It is meant for local runs
+// and tests without Postgres, not for production data.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+// maxTeamDepth bounds walks over the team hierarchy, as in the Postgres repository.
+const maxTeamDepth = 32
+
+// Store implements repository.Store. Writers are serialized: a transaction takes the
+// single writer slot on its first write and works on a private copy of the data that
+// replaces the committed copy on Commit. Until then it reads the committed copy, which is
+// never modified, so a transaction that only reads never blocks other writers.
+type Store struct {
+	writer    chan struct{}
+	mu        sync.RWMutex
+	committed *state
+}
+
+var _ repository.Store = (*Store)(nil)
+
+func New() *Store {
+	return &Store{
+		writer:    make(chan struct{}, 1),
+		committed: newState(),
+	}
+}
+
+type userRow struct {
+	models.User
+	seq int64
+}
+
+type prRow struct {
+	models.PullRequest
+	seq int64
+}
+
+type reviewerRow struct {
+	UserID                string
+	FallbackTeam          string
+	AssignedAt            time.Time
+	RemindedAt            *time.Time
+	EscalationAttemptedAt *time.Time
+}
+
+type sequences struct {
+	row        int64
+	review     int64
+	override   int64
+	assignment int64
+	rule       int64
+	webhook    int64
+	delivery   int64
+	outbox     int64
+	absence    int64
+}
+
+// state holds one version of the data. Rows are stored by value and nested slices are
+// replaced rather than appended to, so a shallow copy of every collection is a snapshot.
+type state struct {
+	teams       map[string]models.Team
+	fallbacks   map[string][]string
+	users       map[string]userRow
+	prs         map[string]prRow
+	reviewers   map[string][]reviewerRow
+	files       map[string][]string
+	reviews     []models.Review
+	overrides   []models.MergeOverride
+	assignments []models.AssignmentEvent
+	rules       []models.OwnershipRule
+	accounts    []models.ExternalAccount
+	webhooks    []models.Webhook
+	deliveries  []models.WebhookDelivery
+	outbox      []models.OutboxEvent
+	absences    []models.Absence
+	idempotency map[idempotencyKeyID]models.IdempotencyKey
+	seq         sequences
+}
+
+func newState() *state {
+	return &state{
+		teams:       make(map[string]models.Team),
+		fallbacks:   make(map[string][]string),
+		users:       make(map[string]userRow),
+		prs:         make(map[string]prRow),
+		reviewers:   make(map[string][]reviewerRow),
+		files:       make(map[string][]string),
+		idempotency: make(map[idempotencyKeyID]models.IdempotencyKey),
+	}
+}
+
+func (st *state) clone() *state {
+	return &state{
+		teams:       maps.Clone(st.teams),
+		fallbacks:   maps.Clone(st.fallbacks),
+		users:       maps.Clone(st.users),
+		prs:         maps.Clone(st.prs),
+		reviewers:   maps.Clone(st.reviewers),
+		files:       maps.Clone(st.files),
+		reviews:     slices.Clone(st.reviews),
+		overrides:   slices.Clone(st.overrides),
+		assignments: slices.Clone(st.assignments),
+		rules:       slices.Clone(st.rules),
+		accounts:    slices.Clone(st.accounts),
+		webhooks:    slices.Clone(st.webhooks),
+		deliveries:  slices.Clone(st.deliveries),
+		outbox:      slices.Clone(st.outbox),
+		absences:    slices.Clone(st.absences),
+		idempotency: maps.Clone(st.idempotency),
+		seq:         st.seq,
+	}
+}
+
+type tx struct {
+	store *Store
+	ctx   context.Context
+	state *state
+	done  bool
+}
+
+// Commit publishes the changes unless the context of the transaction is already done,
+// in which case the transaction is rolled back like a database/sql one would be.
+func (t *tx) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.state == nil {
+		return t.ctx.Err()
+	}
+	defer t.store.release()
+
+	if err := t.ctx.Err(); err != nil {
+		return err
+	}
+
+	t.store.mu.Lock()
+	t.store.committed = t.state
+	t.store.mu.Unlock()
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.state != nil {
+		t.store.release()
+	}
+	return nil
+}
+
+// lock takes the writer slot and a private copy of the latest committed data. Nothing
+// has been written before that, so the copy loses no changes of the transaction.
+func (t *tx) lock() error {
+	if t.state != nil {
+		return nil
+	}
+	select {
+	case t.store.writer <- struct{}{}:
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	}
+
+	t.store.mu.RLock()
+	t.state = t.store.committed.clone()
+	t.store.mu.RUnlock()
+	return nil
+}
+
+func (s *Store) BeginTx(ctx context.Context) (repository.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &tx{store: s, ctx: ctx}, nil
+}
+
+func (s *Store) release() {
+	<-s.writer
+}
+
+// read returns the last committed state. It must not be modified.
+func (s *Store) read(ctx context.Context) (*state, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.committed, nil
+}
+
+// write runs fn in its own transaction.
+func (s *Store) write(ctx context.Context, fn func(st *state) error) error {
+	t := &tx{store: s, ctx: ctx}
+	if err := t.lock(); err != nil {
+		return err
+	}
+	defer t.Rollback()
+
+	if err := fn(t.state); err != nil {
+		return err
+	}
+	return t.Commit()
+}
+
+// readTx returns what the transaction sees: its own copy once it has written, the last
+// committed state before that. The result must not be modified.
+func (s *Store) readTx(ctx context.Context, rtx repository.Tx) (*state, error) {
+	t, err := s.openTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	if t.state != nil {
+		return t.state, nil
+	}
+	return s.read(ctx)
+}
+
+// writeTx returns the working copy of the transaction, taking the writer slot if needed.
+func (s *Store) writeTx(ctx context.Context, rtx repository.Tx) (*state, error) {
+	t, err := s.openTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.lock(); err != nil {
+		return nil, err
+	}
+	return t.state, nil
+}
+
+func (s *Store) openTx(ctx context.Context, rtx repository.Tx) (*tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	t, ok := rtx.(*tx)
+	if !ok || t.store != s {
+		return nil, fmt.Errorf("memory: transaction was not opened by this store")
+	}
+	if t.done {
+		return nil, sql.ErrTxDone
+	}
+	return t, nil
+}
+
+func (st *state) nextRowSeq() int64 {
+	st.seq.row++
+	return st.seq.row
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+func constraintError(format string, args ...interface{}) error {
+	return fmt.Errorf("memory: "+format, args...)
+}
@@ -0,0 +1,451 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+// teamRow keeps only the columns of the teams table.
+func teamRow(team *models.Team) models.Team {
+	return models.Team{
+		TeamName:                team.TeamName,
+		ParentTeam:              team.ParentTeam,
+		InheritPolicy:           team.InheritPolicy,
+		ReviewerStrategy:        team.ReviewerStrategy,
+		MinReviewers:            team.MinReviewers,
+		MaxReviewers:            team.MaxReviewers,
+		RequiredApprovals:       team.RequiredApprovals,
+		BlockOnChangesRequested: team.BlockOnChangesRequested,
+		RequireOwnerApproval:    team.RequireOwnerApproval,
+		ReviewSLAMinutes:        team.ReviewSLAMinutes,
+		ReviewEscalationMinutes: team.ReviewEscalationMinutes,
+	}
+}
+
+func (st *state) checkParent(team *models.Team) error {
+	if team.ParentTeam == "" {
+		return nil
+	}
+	if team.ParentTeam == team.TeamName {
+		return constraintError("team %q cannot be its own parent", team.TeamName)
+	}
+	if _, ok := st.teams[team.ParentTeam]; !ok {
+		return constraintError("parent team %q does not exist", team.ParentTeam)
+	}
+	return nil
+}
+
+func (s *Store) CreateTeam(ctx context.Context, team *models.Team) error {
+	return s.write(ctx, func(st *state) error {
+		return st.createTeam(team)
+	})
+}
+
+func (s *Store) CreateTeamInTx(ctx context.Context, rtx repository.Tx, team *models.Team) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.createTeam(team)
+}
+
+func (st *state) createTeam(team *models.Team) error {
+	if _, ok := st.teams[team.TeamName]; ok {
+		return fmt.Errorf("memory: team %q: %w", team.TeamName, repository.ErrAlreadyExists)
+	}
+	if err := st.checkParent(team); err != nil {
+		return err
+	}
+	st.teams[team.TeamName] = teamRow(team)
+	return nil
+}
+
+func (s *Store) UpdateTeamSettingsInTx(ctx context.Context, rtx repository.Tx, team *models.Team) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	if _, ok := st.teams[team.TeamName]; !ok {
+		return nil
+	}
+	if err := st.checkParent(team); err != nil {
+		return err
+	}
+	st.teams[team.TeamName] = teamRow(team)
+	return nil
+}
+
+func (s *Store) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := st.teams[teamName]
+	return ok, nil
+}
+
+func (s *Store) TeamExistsInTx(ctx context.Context, rtx repository.Tx, teamName string) (bool, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := st.teams[teamName]
+	return ok, nil
+}
+
+func (s *Store) GetTeamRow(ctx context.Context, teamName string) (*models.Team, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, ok := st.teams[teamName]
+	if !ok {
+		return nil, nil
+	}
+	return &row, nil
+}
+
+// LockTeamInTx takes the writer slot, which is the only lock the store has.
+func (s *Store) LockTeamInTx(ctx context.Context, rtx repository.Tx, teamName string) (*models.Team, error) {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	row, ok := st.teams[teamName]
+	if !ok {
+		return nil, nil
+	}
+	return &row, nil
+}
+
+// teamSettings mirrors Repository.GetTeamSettings.
+func (st *state) teamSettings(teamName string) *models.Team {
+	row, ok := st.teams[teamName]
+	if !ok {
+		return nil
+	}
+	team := row
+	team.Ancestors = st.ancestors(teamName)
+
+	if team.InheritPolicy {
+		for _, ancestorName := range team.Ancestors {
+			ancestor, ok := st.teams[ancestorName]
+			if !ok {
+				break
+			}
+			team.CopyPolicy(&ancestor)
+			if !ancestor.InheritPolicy {
+				break
+			}
+		}
+	}
+
+	team.FallbackTeams = append([]string{}, st.fallbacks[teamName]...)
+	return &team
+}
+
+func (s *Store) GetTeamSettings(ctx context.Context, teamName string) (*models.Team, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.teamSettings(teamName), nil
+}
+
+func (s *Store) SetTeamFallbacksInTx(ctx context.Context, rtx repository.Tx, teamName string, fallbacks []string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.setTeamFallbacks(teamName, fallbacks)
+}
+
+func (st *state) setTeamFallbacks(teamName string, fallbacks []string) error {
+	if _, ok := st.teams[teamName]; !ok {
+		return constraintError("team %q does not exist", teamName)
+	}
+	for _, fallback := range fallbacks {
+		if _, ok := st.teams[fallback]; !ok {
+			return constraintError("fallback team %q does not exist", fallback)
+		}
+		if fallback == teamName {
+			return constraintError("team %q cannot be its own fallback", teamName)
+		}
+	}
+	if len(fallbacks) == 0 {
+		delete(st.fallbacks, teamName)
+		return nil
+	}
+	st.fallbacks[teamName] = slices.Clone(fallbacks)
+	return nil
+}
+
+func (s *Store) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	team := st.teamSettings(teamName)
+	if team == nil {
+		return nil, nil
+	}
+
+	var members []models.TeamMember
+	for _, user := range st.usersWhere(func(user *models.User) bool { return user.TeamName == teamName }) {
+		members = append(members, models.TeamMember{
+			UserID:         user.UserID,
+			Username:       user.Username,
+			IsActive:       user.IsActive,
+			ReviewWeight:   user.ReviewWeight,
+			MaxOpenReviews: user.MaxOpenReviews,
+		})
+	}
+	team.Members = members
+
+	return team, nil
+}
+
+// AddTeamMemberInTx mirrors the upsert of the Postgres repository: an existing user is
+// only taken over if they currently have no team.
+func (s *Store) AddTeamMemberInTx(ctx context.Context, rtx repository.Tx, user *models.User) (bool, error) {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return false, err
+	}
+
+	existing, ok := st.users[user.UserID]
+	if !ok {
+		return true, st.insertUser(user)
+	}
+	if existing.TeamName != "" {
+		return false, nil
+	}
+	if err := st.checkUser(user); err != nil {
+		return false, err
+	}
+	existing.User = *user
+	st.users[user.UserID] = existing
+	return true, nil
+}
+
+func (s *Store) MoveUserInTx(ctx context.Context, rtx repository.Tx, userID, teamName string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	if _, ok := st.teams[teamName]; !ok {
+		return constraintError("team %q does not exist", teamName)
+	}
+	return st.updateUser(userID, func(user *models.User) { user.TeamName = teamName })
+}
+
+func (s *Store) DetachUserInTx(ctx context.Context, rtx repository.Tx, userID string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.updateUser(userID, func(user *models.User) { user.TeamName = "" })
+}
+
+// RenameTeamInTx carries the new name over to everything that references the team, like
+// ON UPDATE CASCADE does in Postgres, and to the fallback team of reviewer assignments.
+func (s *Store) RenameTeamInTx(ctx context.Context, rtx repository.Tx, teamName, newName string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+
+	team, ok := st.teams[teamName]
+	if !ok {
+		return nil
+	}
+	if _, ok := st.teams[newName]; ok {
+		return fmt.Errorf("memory: team %q: %w", newName, repository.ErrAlreadyExists)
+	}
+
+	delete(st.teams, teamName)
+	team.TeamName = newName
+	st.teams[newName] = team
+
+	for name, row := range st.teams {
+		if row.ParentTeam == teamName {
+			row.ParentTeam = newName
+			st.teams[name] = row
+		}
+	}
+
+	renamed := make(map[string][]string, len(st.fallbacks))
+	for name, fallbacks := range st.fallbacks {
+		if name == teamName {
+			name = newName
+		}
+		renamed[name] = replaceAll(fallbacks, teamName, newName)
+	}
+	st.fallbacks = renamed
+
+	for id, user := range st.users {
+		if user.TeamName == teamName {
+			user.TeamName = newName
+			st.users[id] = user
+		}
+	}
+	for i := range st.rules {
+		if st.rules[i].OwnerTeamName == teamName {
+			st.rules[i].OwnerTeamName = newName
+		}
+	}
+	for i := range st.webhooks {
+		if st.webhooks[i].TeamName == teamName {
+			st.webhooks[i].TeamName = newName
+		}
+	}
+	for prID, reviewers := range st.reviewers {
+		if !slices.ContainsFunc(reviewers, func(r reviewerRow) bool { return r.FallbackTeam == teamName }) {
+			continue
+		}
+		reviewers = slices.Clone(reviewers)
+		for i := range reviewers {
+			if reviewers[i].FallbackTeam == teamName {
+				reviewers[i].FallbackTeam = newName
+			}
+		}
+		st.reviewers[prID] = reviewers
+	}
+	return nil
+}
+
+// DeleteTeam removes the team only if it has neither members nor sub-teams. Fallback
+// links, ownership rules and webhooks of the team go with it.
+func (s *Store) DeleteTeam(ctx context.Context, teamName string) (bool, error) {
+	deleted := false
+	err := s.write(ctx, func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			return nil
+		}
+		for _, user := range st.users {
+			if user.TeamName == teamName {
+				return nil
+			}
+		}
+		for _, team := range st.teams {
+			if team.ParentTeam == teamName {
+				return nil
+			}
+		}
+
+		delete(st.teams, teamName)
+		delete(st.fallbacks, teamName)
+		for name, fallbacks := range st.fallbacks {
+			if slices.Contains(fallbacks, teamName) {
+				st.fallbacks[name] = slices.DeleteFunc(slices.Clone(fallbacks), func(f string) bool { return f == teamName })
+			}
+		}
+		st.rules = slices.DeleteFunc(st.rules, func(rule models.OwnershipRule) bool {
+			return rule.OwnerTeamName == teamName
+		})
+
+		var webhookIDs []int
+		st.webhooks = slices.DeleteFunc(st.webhooks, func(webhook models.Webhook) bool {
+			if webhook.TeamName == teamName {
+				webhookIDs = append(webhookIDs, webhook.ID)
+				return true
+			}
+			return false
+		})
+		st.deleteDeliveries(webhookIDs...)
+
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// ancestors returns the parent of the team, its parent and so on up to the root.
+func (st *state) ancestors(teamName string) []string {
+	ancestors := []string{}
+	team, ok := st.teams[teamName]
+	for depth := 0; ok && team.ParentTeam != "" && depth < maxTeamDepth; depth++ {
+		parent, exists := st.teams[team.ParentTeam]
+		if !exists {
+			break
+		}
+		ancestors = append(ancestors, parent.TeamName)
+		team = parent
+	}
+	return ancestors
+}
+
+func (s *Store) GetTeamAncestors(ctx context.Context, teamName string) ([]string, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.ancestors(teamName), nil
+}
+
+func (s *Store) GetTeamAncestorsInTx(ctx context.Context, rtx repository.Tx, teamName string) ([]string, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	return st.ancestors(teamName), nil
+}
+
+func (s *Store) GetTeamNames(ctx context.Context) ([]string, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.teamNames(func(models.Team) bool { return true }), nil
+}
+
+func (s *Store) GetSubTeams(ctx context.Context, teamName string) ([]string, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.teamNames(func(team models.Team) bool { return team.ParentTeam == teamName }), nil
+}
+
+func (s *Store) GetTeamParents(ctx context.Context) (map[string]string, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make(map[string]string)
+	for name, team := range st.teams {
+		if team.ParentTeam != "" {
+			parents[name] = team.ParentTeam
+		}
+	}
+	return parents, nil
+}
+
+func (st *state) teamNames(match func(models.Team) bool) []string {
+	names := []string{}
+	for name, team := range st.teams {
+		if match(team) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func replaceAll(values []string, old, new string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		if value == old {
+			value = new
+		}
+		result[i] = value
+	}
+	return result
+}
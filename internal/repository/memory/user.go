@@ -0,0 +1,247 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (st *state) checkUser(user *models.User) error {
+	if user.TeamName != "" {
+		if _, ok := st.teams[user.TeamName]; !ok {
+			return constraintError("team %q does not exist", user.TeamName)
+		}
+	}
+	if user.ReviewWeight <= 0 {
+		return constraintError("review weight of %q must be positive", user.UserID)
+	}
+	if user.MaxOpenReviews != nil && *user.MaxOpenReviews < 0 {
+		return constraintError("max open reviews of %q must not be negative", user.UserID)
+	}
+	return nil
+}
+
+func (st *state) insertUser(user *models.User) error {
+	if _, ok := st.users[user.UserID]; ok {
+		return constraintError("user %q already exists", user.UserID)
+	}
+	if err := st.checkUser(user); err != nil {
+		return err
+	}
+	st.users[user.UserID] = userRow{User: *user, seq: st.nextRowSeq()}
+	return nil
+}
+
+// updateUser applies change to the user if it exists, like an UPDATE matching no rows.
+func (st *state) updateUser(userID string, change func(user *models.User)) error {
+	row, ok := st.users[userID]
+	if !ok {
+		return nil
+	}
+	change(&row.User)
+	st.users[userID] = row
+	return nil
+}
+
+// usersWhere returns matching users in insertion order.
+func (st *state) usersWhere(match func(user *models.User) bool) []models.User {
+	var rows []userRow
+	for _, row := range st.users {
+		if match(&row.User) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq < rows[j].seq })
+
+	var users []models.User
+	for _, row := range rows {
+		users = append(users, row.User)
+	}
+	return users
+}
+
+func (s *Store) CreateUser(ctx context.Context, user *models.User) error {
+	return s.write(ctx, func(st *state) error {
+		return st.insertUser(user)
+	})
+}
+
+func (s *Store) UpdateUsernameInTx(ctx context.Context, rtx repository.Tx, userID, username string) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.updateUser(userID, func(user *models.User) { user.Username = username })
+}
+
+func (s *Store) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, ok := st.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	user := row.User
+	return &user, nil
+}
+
+// LockUserInTx takes the writer slot, which is the only lock the store has.
+func (s *Store) LockUserInTx(ctx context.Context, rtx repository.Tx, userID string) (*models.User, error) {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	row, ok := st.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	user := row.User
+	return &user, nil
+}
+
+func (s *Store) UpdateUserActive(ctx context.Context, userID string, isActive bool) error {
+	return s.write(ctx, func(st *state) error {
+		return st.updateUserActive(userID, isActive)
+	})
+}
+
+func (s *Store) UpdateUserActiveInTx(ctx context.Context, rtx repository.Tx, userID string, isActive bool) error {
+	st, err := s.writeTx(ctx, rtx)
+	if err != nil {
+		return err
+	}
+	return st.updateUserActive(userID, isActive)
+}
+
+// updateUserActive queues a user.deactivated outbox event when an active user is deactivated.
+func (st *state) updateUserActive(userID string, isActive bool) error {
+	row, ok := st.users[userID]
+	if !ok || row.IsActive == isActive {
+		return nil
+	}
+	row.IsActive = isActive
+	st.users[userID] = row
+	if isActive {
+		return nil
+	}
+
+	data := models.UserDeactivatedData{UserID: userID, TeamName: row.TeamName}
+	return st.insertOutboxEvent(models.EventUserDeactivated, row.TeamName, data)
+}
+
+func (s *Store) UpdateUserReviewLimit(ctx context.Context, userID string, maxOpenReviews *int) error {
+	return s.write(ctx, func(st *state) error {
+		if maxOpenReviews != nil && *maxOpenReviews < 0 {
+			return constraintError("max open reviews of %q must not be negative", userID)
+		}
+		return st.updateUser(userID, func(user *models.User) {
+			if maxOpenReviews == nil {
+				user.MaxOpenReviews = nil
+				return
+			}
+			limit := *maxOpenReviews
+			user.MaxOpenReviews = &limit
+		})
+	})
+}
+
+func (s *Store) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.activeUsersByTeam(teamName), nil
+}
+
+func (s *Store) GetActiveUsersByTeamInTx(ctx context.Context, rtx repository.Tx, teamName string) ([]models.User, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	return st.activeUsersByTeam(teamName), nil
+}
+
+func (st *state) activeUsersByTeam(teamName string) []models.User {
+	return st.usersWhere(func(user *models.User) bool {
+		return user.TeamName == teamName && user.IsActive
+	})
+}
+
+func (s *Store) IsUserInOtherTeam(ctx context.Context, userID, teamName string) (bool, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return false, err
+	}
+	row, ok := st.users[userID]
+	return ok && row.TeamName != "" && row.TeamName != teamName, nil
+}
+
+func (s *Store) GetReviewCandidates(ctx context.Context, teamName string) ([]models.ReviewCandidate, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.reviewCandidates(teamName), nil
+}
+
+func (s *Store) GetReviewCandidatesInTx(ctx context.Context, rtx repository.Tx, teamName string) ([]models.ReviewCandidate, error) {
+	st, err := s.readTx(ctx, rtx)
+	if err != nil {
+		return nil, err
+	}
+	return st.reviewCandidates(teamName), nil
+}
+
+// reviewCandidates returns active members of the team who are not inside an absence period
+// right now, with their open review count and their latest assignment event.
+func (st *state) reviewCandidates(teamName string) []models.ReviewCandidate {
+	at := now()
+
+	absent := make(map[string]bool)
+	for _, absence := range st.absences {
+		if absence.ActiveAt(at) {
+			absent[absence.UserID] = true
+		}
+	}
+
+	openReviews := make(map[string]int)
+	for prID, reviewers := range st.reviewers {
+		if st.prs[prID].Status != models.PRStatusOpen {
+			continue
+		}
+		for _, reviewer := range reviewers {
+			openReviews[reviewer.UserID]++
+		}
+	}
+
+	lastAssignment := make(map[string]int64)
+	for _, event := range st.assignments {
+		if event.Action == models.AssignmentAssigned || event.Action == models.AssignmentReplaced {
+			lastAssignment[event.UserID] = event.ID
+		}
+	}
+
+	var candidates []models.ReviewCandidate
+	for _, row := range st.users {
+		if row.TeamName != teamName || !row.IsActive || absent[row.UserID] {
+			continue
+		}
+		candidate := models.ReviewCandidate{
+			UserID:           row.UserID,
+			Weight:           row.ReviewWeight,
+			OpenReviews:      openReviews[row.UserID],
+			LastAssignmentID: lastAssignment[row.UserID],
+		}
+		if row.MaxOpenReviews != nil {
+			limit := *row.MaxOpenReviews
+			candidate.MaxOpenReviews = &limit
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].UserID < candidates[j].UserID })
+	return candidates
+}
@@ -0,0 +1,227 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.teams[webhook.TeamName]; !ok {
+			return constraintError("team %q does not exist", webhook.TeamName)
+		}
+
+		st.seq.webhook++
+		webhook.ID = int(st.seq.webhook)
+		webhook.CreatedAt = now()
+
+		row := *webhook
+		row.EventTypes = append([]string{}, webhook.EventTypes...)
+		st.webhooks = append(st.webhooks, row)
+		return nil
+	})
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id int) (*models.Webhook, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range st.webhooks {
+		if webhook.ID == id {
+			webhook.EventTypes = slices.Clone(webhook.EventTypes)
+			return &webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) GetWebhooksByTeam(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	return s.webhooksWhere(ctx, func(webhook *models.Webhook) bool { return webhook.TeamName == teamName })
+}
+
+func (s *Store) GetActiveWebhooksByTeam(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	return s.webhooksWhere(ctx, func(webhook *models.Webhook) bool {
+		return webhook.TeamName == teamName && webhook.IsActive
+	})
+}
+
+func (s *Store) webhooksWhere(ctx context.Context, match func(webhook *models.Webhook) bool) ([]models.Webhook, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []models.Webhook{}
+	for _, webhook := range st.webhooks {
+		if match(&webhook) {
+			webhook.EventTypes = slices.Clone(webhook.EventTypes)
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook also removes the deliveries of the webhook, like ON DELETE CASCADE.
+func (s *Store) DeleteWebhook(ctx context.Context, id int) (bool, error) {
+	deleted := false
+	err := s.write(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.webhooks, func(webhook models.Webhook) bool { return webhook.ID == id })
+		if i < 0 {
+			return nil
+		}
+		st.webhooks = slices.Delete(st.webhooks, i, i+1)
+		st.deleteDeliveries(id)
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+func (st *state) deleteDeliveries(webhookIDs ...int) {
+	if len(webhookIDs) == 0 {
+		return
+	}
+	st.deliveries = slices.DeleteFunc(st.deliveries, func(delivery models.WebhookDelivery) bool {
+		return slices.Contains(webhookIDs, delivery.WebhookID)
+	})
+}
+
+// CreateWebhookDelivery reports false when a delivery of the same outbox event to the
+// same webhook already exists, which happens when the outbox dispatcher retries an event.
+func (s *Store) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	created := false
+	err := s.write(ctx, func(st *state) error {
+		if !slices.ContainsFunc(st.webhooks, func(webhook models.Webhook) bool { return webhook.ID == delivery.WebhookID }) {
+			return constraintError("webhook %d does not exist", delivery.WebhookID)
+		}
+		if delivery.OutboxEventID != nil && slices.ContainsFunc(st.deliveries, func(existing models.WebhookDelivery) bool {
+			return existing.WebhookID == delivery.WebhookID && existing.OutboxEventID != nil &&
+				*existing.OutboxEventID == *delivery.OutboxEventID
+		}) {
+			return nil
+		}
+
+		st.seq.delivery++
+		delivery.ID = st.seq.delivery
+		delivery.CreatedAt = now()
+		st.deliveries = append(st.deliveries, copyDelivery(*delivery))
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// copyDelivery detaches the delivery from pointers and buffers owned by the caller.
+func copyDelivery(delivery models.WebhookDelivery) models.WebhookDelivery {
+	delivery.Payload = slices.Clone(delivery.Payload)
+	if delivery.ResponseCode != nil {
+		code := *delivery.ResponseCode
+		delivery.ResponseCode = &code
+	}
+	if delivery.ReplayOf != nil {
+		replayOf := *delivery.ReplayOf
+		delivery.ReplayOf = &replayOf
+	}
+	if delivery.OutboxEventID != nil {
+		outboxEventID := *delivery.OutboxEventID
+		delivery.OutboxEventID = &outboxEventID
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		delivery.DeliveredAt = &deliveredAt
+	}
+	return delivery
+}
+
+func (s *Store) GetWebhookDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, delivery := range st.deliveries {
+		if delivery.ID == id {
+			delivery = copyDelivery(delivery)
+			return &delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) GetWebhookDeliveries(ctx context.Context, webhookID int, status string) ([]models.WebhookDelivery, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range st.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *Store) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range st.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// ClaimWebhookDelivery pushes next_attempt_at forward so that other workers skip
+// the delivery while it is being sent. It reports false if someone else claimed it first.
+func (s *Store) ClaimWebhookDelivery(ctx context.Context, id int64, now, leaseUntil time.Time) (bool, error) {
+	claimed := false
+	err := s.write(ctx, func(st *state) error {
+		i := st.delivery(id)
+		if i < 0 || st.deliveries[i].Status != models.DeliveryPending || st.deliveries[i].NextAttemptAt.After(now) {
+			return nil
+		}
+		st.deliveries[i].NextAttemptAt = leaseUntil
+		claimed = true
+		return nil
+	})
+	return claimed, err
+}
+
+func (s *Store) UpdateWebhookDeliveryResult(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return s.write(ctx, func(st *state) error {
+		i := st.delivery(delivery.ID)
+		if i < 0 {
+			return nil
+		}
+		updated := copyDelivery(*delivery)
+		row := &st.deliveries[i]
+		row.Status = updated.Status
+		row.Attempts = updated.Attempts
+		row.ResponseCode = updated.ResponseCode
+		row.LastError = updated.LastError
+		row.NextAttemptAt = updated.NextAttemptAt
+		row.DeliveredAt = updated.DeliveredAt
+		return nil
+	})
+}
+
+func (st *state) delivery(id int64) int {
+	return slices.IndexFunc(st.deliveries, func(delivery models.WebhookDelivery) bool { return delivery.ID == id })
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (r *Repository) CreateMergeOverrideInTx(ctx context.Context, tx Tx, override *models.MergeOverride) error {
+	query := `INSERT INTO merge_overrides (pr_id, actor, requested_by, reason, unmet_rules) 
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return sqlTx(tx).QueryRowContext(ctx, query, override.PullRequestID, override.Actor, nullString(override.RequestedBy),
+		nullString(override.Reason), strings.Join(override.UnmetRules, "\n")).Scan(&override.ID, &override.CreatedAt)
+}
+
+func (r *Repository) GetMergeOverrides(ctx context.Context, prID string) ([]models.MergeOverride, error) {
+	query := `SELECT id, pr_id, actor, requested_by, reason, unmet_rules, created_at 
+	          FROM merge_overrides WHERE pr_id = $1 ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []models.MergeOverride{}
+	for rows.Next() {
+		var override models.MergeOverride
+		var requestedBy, reason sql.NullString
+		var unmetRules string
+		if err := rows.Scan(&override.ID, &override.PullRequestID, &override.Actor, &requestedBy, &reason, &unmetRules,
+			&override.CreatedAt); err != nil {
+			return nil, err
+		}
+		override.RequestedBy = requestedBy.String
+		override.Reason = reason.String
+		override.UnmetRules = []string{}
+		if unmetRules != "" {
+			override.UnmetRules = strings.Split(unmetRules, "\n")
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, rows.Err()
+}
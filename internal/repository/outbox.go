@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (r *Repository) insertOutboxEvent(ctx context.Context, q queryer, eventType, teamName string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox_events (event_type, team_name, payload, occurred_at, next_attempt_at) VALUES ($1, $2, $3, $4, $4) RETURNING id`
+	var id int64
+	if err := q.QueryRowContext(ctx, query, eventType, teamName, string(payload), time.Now().UTC()).Scan(&id); err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "outbox event queued", "event_id", id, "event_type", eventType)
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit due events in insertion order and pushes their
+// next_attempt_at to leaseUntil, so other dispatchers skip them while they are published.
+// The claim is a single statement: no transaction stays open while sinks run.
+func (r *Repository) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE published_at IS NULL AND dead_lettered_at IS NULL AND next_attempt_at <= $2
+			ORDER BY id
+			LIMIT $3
+			` + r.dialect.forUpdateSkipLocked + `
+		)
+		RETURNING id, event_type, team_name, payload, occurred_at, attempts, last_error, next_attempt_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, leaseUntil, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.OutboxEvent{}
+	for rows.Next() {
+		var event models.OutboxEvent
+		var payload string
+		var lastError sql.NullString
+		if err := rows.Scan(&event.ID, &event.Type, &event.TeamName, &payload, &event.OccurredAt,
+			&event.Attempts, &lastError, &event.NextAttemptAt); err != nil {
+			return nil, err
+		}
+		event.Data = json.RawMessage(payload)
+		event.LastError = lastError.String
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (r *Repository) UpdateOutboxEventResult(ctx context.Context, event *models.OutboxEvent) error {
+	query := `UPDATE outbox_events 
+	          SET attempts = $1, last_error = $2, next_attempt_at = $3, published_at = $4, dead_lettered_at = $5 
+	          WHERE id = $6`
+	var publishedAt, deadLetteredAt interface{}
+	if event.PublishedAt != nil {
+		publishedAt = *event.PublishedAt
+	}
+	if event.DeadLetteredAt != nil {
+		deadLetteredAt = *event.DeadLetteredAt
+	}
+	_, err := r.db.ExecContext(ctx, query, event.Attempts, nullString(event.LastError), event.NextAttemptAt,
+		publishedAt, deadLetteredAt, event.ID)
+	return err
+}
+
+func userTeam(ctx context.Context, q queryer, userID string) (string, error) {
+	var teamName string
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(team_name, '') FROM users WHERE user_id = $1`, userID).Scan(&teamName)
+	return teamName, err
+}
+
+// prTeam returns the team of the PR author, which is the team that receives events about the PR.
+func prTeam(ctx context.Context, q queryer, prID string) (string, error) {
+	query := `SELECT COALESCE(u.team_name, '') FROM pull_requests pr JOIN users u ON u.user_id = pr.author_id
+	          WHERE pr.pull_request_id = $1`
+	var teamName string
+	err := q.QueryRowContext(ctx, query, prID).Scan(&teamName)
+	return teamName, err
+}
+
+// AssignmentEventType maps an assignment action to the outbox event it produces.
+func AssignmentEventType(action string) string {
+	switch action {
+	case models.AssignmentReplaced:
+		return models.EventReviewerReassigned
+	case models.AssignmentRemoved:
+		return models.EventReviewerRemoved
+	default:
+		return models.EventReviewerAssigned
+	}
+}
+
+// PRStatusEventType maps a PR status to the outbox event it produces. Drafts produce none.
+func PRStatusEventType(status string) string {
+	switch status {
+	case models.PRStatusOpen:
+		return models.EventPROpened
+	case models.PRStatusMerged:
+		return models.EventPRMerged
+	case models.PRStatusClosed:
+		return models.EventPRClosed
+	default:
+		return ""
+	}
+}
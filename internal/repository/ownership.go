@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (r *Repository) ReplaceOwnershipRules(ctx context.Context, rules []models.OwnershipRule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM ownership_rules")
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO ownership_rules (position, pattern, owner_user_id, owner_team_name) VALUES ($1, $2, $3, $4)`,
+			rule.Position, rule.Pattern, nullString(rule.OwnerUserID), nullString(rule.OwnerTeamName),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetOwnershipRules(ctx context.Context) ([]models.OwnershipRule, error) {
+	query := `SELECT id, position, pattern, owner_user_id, owner_team_name 
+	          FROM ownership_rules ORDER BY position, id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.OwnershipRule{}
+	for rows.Next() {
+		var rule models.OwnershipRule
+		var ownerUserID, ownerTeamName sql.NullString
+		if err := rows.Scan(&rule.ID, &rule.Position, &rule.Pattern, &ownerUserID, &ownerTeamName); err != nil {
+			return nil, err
+		}
+		rule.OwnerUserID = ownerUserID.String
+		rule.OwnerTeamName = ownerTeamName.String
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *Repository) GetPRFiles(ctx context.Context, prID string) ([]string, error) {
+	return r.getPRFiles(ctx, r.db, prID)
+}
+
+func (r *Repository) getPRFiles(ctx context.Context, q queryer, prID string) ([]string, error) {
+	query := `SELECT path FROM pr_files WHERE pr_id = $1 ORDER BY path`
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+	return files, rows.Err()
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
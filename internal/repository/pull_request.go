@@ -1,45 +1,77 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/lypolix/avito_test/internal/logging"
 	"github.com/lypolix/avito_test/internal/models"
 )
 
-func (r *Repository) CreatePR(pr *models.PullRequest) error {
-	tx, err := r.db.Begin()
+func (r *Repository) CreatePR(ctx context.Context, pr *models.PullRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	if err := r.CreatePRInTx(ctx, tx, pr); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) CreatePRInTx(ctx context.Context, tx Tx, pr *models.PullRequest) error {
 	query := `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status) 
 	          VALUES ($1, $2, $3, $4)`
-	_, err = tx.Exec(query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status)
+	_, err := sqlTx(tx).ExecContext(ctx, query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status)
 	if err != nil {
 		return err
 	}
 
-	for _, reviewerID := range pr.AssignedReviewers {
-		query = `INSERT INTO pr_reviewers (pr_id, user_id) VALUES ($1, $2)`
-		_, err = tx.Exec(query, pr.PullRequestID, reviewerID)
+	if err := insertPRReviewers(ctx, sqlTx(tx), pr.PullRequestID, pr.AssignedReviewers, pr.ReviewerFallbackTeams); err != nil {
+		return err
+	}
+
+	for _, path := range pr.ChangedFiles {
+		query = `INSERT INTO pr_files (pr_id, path) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		_, err = sqlTx(tx).ExecContext(ctx, query, pr.PullRequestID, path)
 		if err != nil {
 			return err
 		}
 	}
 
-	return tx.Commit()
+	teamName, err := userTeam(ctx, sqlTx(tx), pr.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	return r.insertOutboxEvent(ctx, sqlTx(tx), models.EventPRCreated, teamName, pr)
 }
 
-func (r *Repository) GetPR(prID string) (*models.PullRequest, error) {
-	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at 
+func (r *Repository) GetPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return r.getPR(ctx, r.db, prID)
+}
+
+// GetPRInTx reads the PR with its reviewers, their states and its files through tx, so
+// a caller holding the lock from LockPRInTx sees the rows as they are under that lock.
+func (r *Repository) GetPRInTx(ctx context.Context, tx Tx, prID string) (*models.PullRequest, error) {
+	return r.getPR(ctx, sqlTx(tx), prID)
+}
+
+func (r *Repository) getPR(ctx context.Context, q queryer, prID string) (*models.PullRequest, error) {
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at 
 	          FROM pull_requests WHERE pull_request_id = $1`
 	var pr models.PullRequest
-	var mergedAt sql.NullTime
-	err := r.db.QueryRow(query, prID).Scan(
+	var mergedAt, closedAt sql.NullTime
+	err := q.QueryRowContext(ctx, query, prID).Scan(
 		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status,
-		&pr.CreatedAt, &mergedAt,
+		&pr.CreatedAt, &mergedAt, &closedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -51,19 +83,75 @@ func (r *Repository) GetPR(prID string) (*models.PullRequest, error) {
 	if mergedAt.Valid {
 		pr.MergedAt = &mergedAt.Time
 	}
+	if closedAt.Valid {
+		pr.ClosedAt = &closedAt.Time
+	}
 
-	reviewers, err := r.GetPRReviewers(prID)
+	reviewers, err := r.getPRReviewers(ctx, q, prID)
 	if err != nil {
 		return nil, err
 	}
 	pr.AssignedReviewers = reviewers
 
+	fallbackReviewers, err := r.getPRFallbackReviewers(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	pr.FallbackReviewers = fallbackReviewers
+
+	files, err := r.getPRFiles(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	pr.ChangedFiles = files
+
+	states, err := r.getReviewerStates(ctx, q, prID, reviewers)
+	if err != nil {
+		return nil, err
+	}
+	pr.ReviewerStates = states
+
 	return &pr, nil
 }
 
-func (r *Repository) GetPRReviewers(prID string) ([]string, error) {
-	query := `SELECT user_id FROM pr_reviewers WHERE pr_id = $1`
-	rows, err := r.db.Query(query, prID)
+// GetPRFallbackReviewers returns reviewers that were drawn from another team when they were
+// assigned, so later changes to fallbacks or team membership do not affect the answer.
+func (r *Repository) GetPRFallbackReviewers(ctx context.Context, prID string) ([]string, error) {
+	return r.getPRFallbackReviewers(ctx, r.db, prID)
+}
+
+func (r *Repository) getPRFallbackReviewers(ctx context.Context, q queryer, prID string) ([]string, error) {
+	query := `SELECT user_id FROM pr_reviewers
+	          WHERE pr_id = $1 AND fallback_team IS NOT NULL
+	          ORDER BY assigned_at, user_id`
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviewers []string
+	for rows.Next() {
+		var reviewerID string
+		if err := rows.Scan(&reviewerID); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, reviewerID)
+	}
+	return reviewers, rows.Err()
+}
+
+func (r *Repository) GetPRReviewers(ctx context.Context, prID string) ([]string, error) {
+	return r.getPRReviewers(ctx, r.db, prID)
+}
+
+func (r *Repository) GetPRReviewersInTx(ctx context.Context, tx Tx, prID string) ([]string, error) {
+	return r.getPRReviewers(ctx, sqlTx(tx), prID)
+}
+
+func (r *Repository) getPRReviewers(ctx context.Context, q queryer, prID string) ([]string, error) {
+	query := `SELECT user_id FROM pr_reviewers WHERE pr_id = $1 ORDER BY assigned_at, user_id`
+	rows, err := q.QueryContext(ctx, query, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,77 +168,152 @@ func (r *Repository) GetPRReviewers(prID string) ([]string, error) {
 	return reviewers, nil
 }
 
-func (r *Repository) UpdatePRStatus(prID, status string) error {
-	query := `UPDATE pull_requests SET status = $1, merged_at = $2 WHERE pull_request_id = $3`
+// LockPRInTx locks the PR row until the transaction ends and returns its status, or an
+// empty status if the PR does not exist. Changes that depend on the PR state take this
+// lock first, so concurrent requests see each other's result instead of overwriting it.
+func (r *Repository) LockPRInTx(ctx context.Context, tx Tx, prID string) (string, error) {
+	query := `SELECT status FROM pull_requests WHERE pull_request_id = $1 ` + r.dialect.forUpdate
+	start := time.Now()
+	var status string
+	err := sqlTx(tx).QueryRowContext(ctx, query, prID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err == nil {
+		ctx = logging.With(ctx, "pr_id", prID)
+		slog.DebugContext(ctx, "pull request locked", "wait_ms", time.Since(start).Milliseconds())
+	}
+	return status, err
+}
+
+func (r *Repository) UpdatePRStatusInTx(ctx context.Context, tx Tx, prID, status string) error {
+	return r.updatePRStatus(ctx, sqlTx(tx), prID, status)
+}
+
+func (r *Repository) updatePRStatus(ctx context.Context, q queryer, prID, status string) error {
+	query := `UPDATE pull_requests SET status = $1, merged_at = $2, closed_at = $3 WHERE pull_request_id = $4`
 
-	var mergedAt interface{}
-	if status == "MERGED" {
+	var mergedAt, closedAt interface{}
+	switch status {
+	case models.PRStatusMerged:
 		mergedAt = time.Now().UTC().Truncate(time.Second)
-	} else {
-		mergedAt = nil
+	case models.PRStatusClosed:
+		closedAt = time.Now().UTC().Truncate(time.Second)
 	}
 
-	_, err := r.db.Exec(query, status, mergedAt, prID)
-	return err
+	if _, err := q.ExecContext(ctx, query, status, mergedAt, closedAt, prID); err != nil {
+		return err
+	}
+
+	eventType := PRStatusEventType(status)
+	if eventType == "" {
+		return nil
+	}
+
+	var pr models.PullRequestShort
+	var teamName string
+	query = `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, COALESCE(u.team_name, '')
+	         FROM pull_requests pr JOIN users u ON u.user_id = pr.author_id
+	         WHERE pr.pull_request_id = $1`
+	if err := q.QueryRowContext(ctx, query, prID).Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
+		&pr.Status, &teamName); err != nil {
+		return err
+	}
+
+	return r.insertOutboxEvent(ctx, q, eventType, teamName, pr)
 }
 
-func (r *Repository) PRExists(prID string) (bool, error) {
+func (r *Repository) PRExists(ctx context.Context, prID string) (bool, error) {
 	query := `SELECT 1 FROM pull_requests WHERE pull_request_id = $1`
 	var exists int
-	err := r.db.QueryRow(query, prID).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, prID).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
 	return exists == 1, err
 }
 
-func (r *Repository) UpdatePRReviewers(prID string, reviewers []string) error {
-	tx, err := r.db.Begin()
+func (r *Repository) UpdatePRReviewers(ctx context.Context, prID string, reviewers []string, fallbackTeams map[string]string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec("DELETE FROM pr_reviewers WHERE pr_id = $1", prID)
+	if err := r.UpdatePRReviewersInTx(ctx, tx, prID, reviewers, fallbackTeams); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdatePRReviewersInTx applies only the difference to pr_reviewers, so reviewers
+// that stay on the PR keep their original assigned_at and fallback team. fallbackTeams
+// gives the team each added reviewer was drawn from, if it is not the author's team.
+func (r *Repository) UpdatePRReviewersInTx(ctx context.Context, tx Tx, prID string, reviewers []string, fallbackTeams map[string]string) error {
+	current, err := r.getPRReviewers(ctx, sqlTx(tx), prID)
 	if err != nil {
 		return err
 	}
 
+	keep := make(map[string]bool)
 	for _, reviewerID := range reviewers {
-		_, err = tx.Exec("INSERT INTO pr_reviewers (pr_id, user_id) VALUES ($1, $2)", prID, reviewerID)
-		if err != nil {
-			return err
+		keep[reviewerID] = true
+	}
+	existing := make(map[string]bool)
+	for _, reviewerID := range current {
+		existing[reviewerID] = true
+		if !keep[reviewerID] {
+			_, err = sqlTx(tx).ExecContext(ctx, "DELETE FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2", prID, reviewerID)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	return tx.Commit()
+	var added []string
+	for _, reviewerID := range reviewers {
+		if !existing[reviewerID] {
+			added = append(added, reviewerID)
+		}
+	}
+
+	return insertPRReviewers(ctx, sqlTx(tx), prID, added, fallbackTeams)
 }
 
-func (r *Repository) UpdatePRReviewersInTx(tx *sql.Tx, prID string, reviewers []string) error {
-	_, err := tx.Exec("DELETE FROM pr_reviewers WHERE pr_id = $1", prID)
-	if err != nil {
-		return err
+// insertPRReviewers adds the reviewers in a single statement, so they all get the same
+// assigned_at on SQLite as well, where the default is taken per statement rather than per
+// transaction.
+func insertPRReviewers(ctx context.Context, q queryer, prID string, reviewers []string, fallbackTeams map[string]string) error {
+	if len(reviewers) == 0 {
+		return nil
 	}
 
+	values := make([]string, 0, len(reviewers))
+	args := []interface{}{prID}
 	for _, reviewerID := range reviewers {
-		_, err = tx.Exec("INSERT INTO pr_reviewers (pr_id, user_id) VALUES ($1, $2)", prID, reviewerID)
-		if err != nil {
-			return err
+		var fallbackTeam interface{}
+		if teamName := fallbackTeams[reviewerID]; teamName != "" {
+			fallbackTeam = teamName
 		}
+		values = append(values, fmt.Sprintf("($1, $%d, $%d)", len(args)+1, len(args)+2))
+		args = append(args, reviewerID, fallbackTeam)
 	}
 
-	return nil
+	query := `INSERT INTO pr_reviewers (pr_id, user_id, fallback_team) VALUES ` + strings.Join(values, ", ")
+	_, err := q.ExecContext(ctx, query, args...)
+	return err
 }
 
-func (r *Repository) GetPRsByReviewer(userID string) ([]models.PullRequestShort, error) {
+func (r *Repository) GetPRsByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
 	query := `
 		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
 		FROM pull_requests pr
 		JOIN pr_reviewers prr ON pr.pull_request_id = prr.pr_id
-		WHERE prr.user_id = $1
+		WHERE prr.user_id = $1 AND pr.status <> 'CLOSED'
 		ORDER BY pr.created_at DESC
 	`
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -167,10 +330,10 @@ func (r *Repository) GetPRsByReviewer(userID string) ([]models.PullRequestShort,
 	return prs, nil
 }
 
-func (r *Repository) GetAllOpenPRs() ([]*models.PullRequest, error) {
+func (r *Repository) GetAllOpenPRs(ctx context.Context) ([]*models.PullRequest, error) {
 	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at 
 	          FROM pull_requests WHERE status = 'OPEN'`
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +354,7 @@ func (r *Repository) GetAllOpenPRs() ([]*models.PullRequest, error) {
 			pr.MergedAt = &mergedAt.Time
 		}
 
-		reviewers, err := r.GetPRReviewers(pr.PullRequestID)
+		reviewers, err := r.GetPRReviewers(ctx, pr.PullRequestID)
 		if err != nil {
 			return nil, err
 		}
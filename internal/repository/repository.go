@@ -1,15 +1,58 @@
 package repository
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
 
+// ErrAlreadyExists is returned when a write would duplicate a unique key.
+var ErrAlreadyExists = errors.New("already exists")
+
+// Repository is the SQL implementation of Store. It runs on Postgres, or on SQLite when
+// created with NewSQLiteRepository.
 type Repository struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect
+}
+
+var _ Store = (*Repository)(nil)
+
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, dialect: postgresDialect}
+}
+
+// NewSQLiteRepository expects db to be opened by database.OpenSQLite.
+func NewSQLiteRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, dialect: sqliteDialect}
+}
+
+// BeginTx starts a transaction bound to ctx. If ctx is cancelled before Commit,
+// database/sql rolls the transaction back.
+func (r *Repository) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// sqlTx unwraps a transaction opened by BeginTx.
+func sqlTx(tx Tx) *sql.Tx {
+	return tx.(*sql.Tx)
 }
 
-func (r *Repository) BeginTx() (*sql.Tx, error) {
-	return r.db.Begin()
+// uniqueViolation wraps a duplicate key error of the database in ErrAlreadyExists.
+func (r *Repository) uniqueViolation(err error) error {
+	if err != nil && r.dialect.isUniqueViolation(err) {
+		return fmt.Errorf("%w: %v", ErrAlreadyExists, err)
+	}
+	return err
 }
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (r *Repository) CreateReviewInTx(ctx context.Context, tx Tx, review *models.Review) error {
+	query := `INSERT INTO pr_reviews (pr_id, user_id, state, comment) 
+	          VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return sqlTx(tx).QueryRowContext(ctx, query, review.PullRequestID, review.UserID, review.State, nullString(review.Comment)).
+		Scan(&review.ID, &review.SubmittedAt)
+}
+
+func (r *Repository) GetPRReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	query := `SELECT id, pr_id, user_id, state, comment, created_at 
+	          FROM pr_reviews WHERE pr_id = $1 ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var review models.Review
+		var comment sql.NullString
+		if err := rows.Scan(&review.ID, &review.PullRequestID, &review.UserID, &review.State, &comment, &review.SubmittedAt); err != nil {
+			return nil, err
+		}
+		review.Comment = comment.String
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *Repository) queryReviewStates(ctx context.Context, q queryer, query string, prID string) (map[string]models.ReviewerState, error) {
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[string]models.ReviewerState)
+	for rows.Next() {
+		var state models.ReviewerState
+		var submittedAt time.Time
+		if err := rows.Scan(&state.UserID, &state.State, &submittedAt); err != nil {
+			return nil, err
+		}
+		state.SubmittedAt = &submittedAt
+		states[state.UserID] = state
+	}
+	return states, rows.Err()
+}
+
+// GetReviewerStates returns the state of each reviewer, counting only reviews submitted
+// since the reviewer was assigned, so a reviewer who is assigned again starts as pending.
+func (r *Repository) GetReviewerStates(ctx context.Context, prID string, reviewers []string) ([]models.ReviewerState, error) {
+	return r.getReviewerStates(ctx, r.db, prID, reviewers)
+}
+
+func (r *Repository) getReviewerStates(ctx context.Context, q queryer, prID string, reviewers []string) ([]models.ReviewerState, error) {
+	query := `
+		SELECT rv.user_id, rv.state, rv.created_at
+		FROM pr_reviews rv
+		JOIN pr_reviewers prr ON prr.pr_id = rv.pr_id AND prr.user_id = rv.user_id
+		WHERE rv.pr_id = $1 AND rv.id = (
+			SELECT MAX(latest.id) FROM pr_reviews latest
+			WHERE latest.pr_id = rv.pr_id AND latest.user_id = rv.user_id
+			  AND latest.created_at >= prr.assigned_at
+		)
+	`
+	latest, err := r.queryReviewStates(ctx, q, query, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]models.ReviewerState, 0, len(reviewers))
+	for _, reviewerID := range reviewers {
+		state, ok := latest[reviewerID]
+		if !ok {
+			state = models.ReviewerState{UserID: reviewerID, State: models.ReviewPending}
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// GetStaleReviews lists reviewers on open PRs who have not submitted a review since they were
+// assigned and whose assignment is older than the SLA of the author's team. The SLA is the
+// effective one, so a team that inherits its policy uses the SLA of the ancestor it inherits
+// from, as in GetTeamSettings. Teams without an SLA are skipped. An empty teamName returns
+// stale reviews of every team. After a failed escalation the next one is due one SLA period
+// later, see RecordEscalationAttempt.
+func (r *Repository) GetStaleReviews(ctx context.Context, teamName string) ([]models.StaleReview, error) {
+	dueAt := r.dialect.addMinutes("prr.assigned_at", "t.review_sla_minutes")
+	escalateAt := r.dialect.addMinutes("prr.assigned_at", "t.review_escalation_minutes")
+	retryAt := r.dialect.addMinutes("prr.escalation_attempted_at", "t.review_sla_minutes")
+	query := `
+		WITH RECURSIVE policy_chain AS (
+		    SELECT team_name, team_name AS source_team, inherit_policy, parent_team, 0 AS depth
+		    FROM teams
+		    UNION ALL
+		    SELECT pc.team_name, t.team_name, t.inherit_policy, t.parent_team, pc.depth + 1
+		    FROM policy_chain pc
+		    JOIN teams t ON t.team_name = pc.parent_team
+		    WHERE pc.inherit_policy AND pc.depth < $2
+		),
+		effective_policy AS (
+		    SELECT team_name, review_sla_minutes, review_escalation_minutes
+		    FROM (
+		        SELECT pc.team_name, src.review_sla_minutes, src.review_escalation_minutes,
+		               ROW_NUMBER() OVER (PARTITION BY pc.team_name ORDER BY pc.depth DESC) AS source_rank
+		        FROM policy_chain pc
+		        JOIN teams src ON src.team_name = pc.source_team
+		    ) ranked
+		    WHERE source_rank = 1
+		)
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, prr.user_id, t.team_name,
+		       prr.assigned_at, t.review_sla_minutes, t.review_escalation_minutes, prr.reminded_at,
+		       prr.escalation_attempted_at,
+		       t.review_escalation_minutes > 0 AND ` + escalateAt + ` <= ` + r.dialect.now + `
+		           AND (prr.escalation_attempted_at IS NULL OR ` + retryAt + ` <= ` + r.dialect.now + `) AS escalation_due
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.pull_request_id = prr.pr_id AND pr.status = 'OPEN'
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN effective_policy t ON t.team_name = a.team_name
+		WHERE t.review_sla_minutes > 0
+		  AND ($1 = '' OR t.team_name = $1)
+		  AND ` + dueAt + ` <= ` + r.dialect.now + `
+		  AND NOT EXISTS (
+		      SELECT 1 FROM pr_reviews rv
+		      WHERE rv.pr_id = prr.pr_id AND rv.user_id = prr.user_id AND rv.created_at >= prr.assigned_at
+		  )
+		ORDER BY ` + dueAt + `, pr.pull_request_id, prr.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamName, maxTeamDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.StaleReview{}
+	for rows.Next() {
+		var review models.StaleReview
+		var slaMinutes, escalationMinutes int
+		var remindedAt, escalationAttemptedAt sql.NullTime
+		if err := rows.Scan(&review.PullRequestID, &review.PullRequestName, &review.AuthorID, &review.ReviewerID,
+			&review.TeamName, &review.AssignedAt, &slaMinutes, &escalationMinutes, &remindedAt, &escalationAttemptedAt,
+			&review.EscalationDue); err != nil {
+			return nil, err
+		}
+		review.DueAt = review.AssignedAt.Add(time.Duration(slaMinutes) * time.Minute)
+		if escalationMinutes > 0 {
+			escalateAt := review.AssignedAt.Add(time.Duration(escalationMinutes) * time.Minute)
+			review.EscalateAt = &escalateAt
+		}
+		if remindedAt.Valid {
+			review.RemindedAt = &remindedAt.Time
+		}
+		if escalationAttemptedAt.Valid {
+			review.EscalationAttemptedAt = &escalationAttemptedAt.Time
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// RecordReviewReminder marks the assignment as reminded and queues a review.reminder outbox
+// event in the same transaction. It reports false if a reminder was already sent.
+func (r *Repository) RecordReviewReminder(ctx context.Context, review *models.StaleReview) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE pr_reviewers SET reminded_at = ` + r.dialect.now + ` 
+	          WHERE pr_id = $1 AND user_id = $2 AND reminded_at IS NULL RETURNING reminded_at`
+	var remindedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, query, review.PullRequestID, review.ReviewerID).Scan(&remindedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	review.RemindedAt = &remindedAt.Time
+
+	if err := r.insertOutboxEvent(ctx, tx, models.EventReviewReminder, review.TeamName, review); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// RecordEscalationAttempt notes that the assignment could not be escalated, so the next attempt
+// is due one SLA period later instead of on every pass.
+func (r *Repository) RecordEscalationAttempt(ctx context.Context, review *models.StaleReview) error {
+	query := `UPDATE pr_reviewers SET escalation_attempted_at = ` + r.dialect.now + `
+	          WHERE pr_id = $1 AND user_id = $2 RETURNING escalation_attempted_at`
+	var attemptedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, review.PullRequestID, review.ReviewerID).Scan(&attemptedAt)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	review.EscalationAttemptedAt = &attemptedAt
+	return nil
+}
@@ -1,19 +1,21 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/lypolix/avito_test/internal/models"
 )
 
-func (r *Repository) GetUserAssignmentStats() ([]models.UserStat, error) {
+func (r *Repository) GetUserAssignmentStats(ctx context.Context) ([]models.UserStat, error) {
 	query := `
 		SELECT 
 			u.user_id,
 			u.username,
-			u.team_name,
+			COALESCE(u.team_name, ''),
 			u.is_active,
-			COUNT(prr.pr_id) as assignments_count
+			COUNT(prr.pr_id) as assignments_count,
+			COUNT(pr.pull_request_id) as open_assignments_count
 		FROM users u
 		LEFT JOIN pr_reviewers prr ON u.user_id = prr.user_id
 		LEFT JOIN pull_requests pr ON prr.pr_id = pr.pull_request_id AND pr.status = 'OPEN'
@@ -21,7 +23,7 @@ func (r *Repository) GetUserAssignmentStats() ([]models.UserStat, error) {
 		ORDER BY assignments_count DESC, u.user_id
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +32,7 @@ func (r *Repository) GetUserAssignmentStats() ([]models.UserStat, error) {
 	var stats []models.UserStat
 	for rows.Next() {
 		var stat models.UserStat
-		err := rows.Scan(&stat.UserID, &stat.Username, &stat.TeamName, &stat.IsActive, &stat.AssignmentsCount)
+		err := rows.Scan(&stat.UserID, &stat.Username, &stat.TeamName, &stat.IsActive, &stat.AssignmentsCount, &stat.OpenAssignmentsCount)
 		if err != nil {
 			return nil, err
 		}
@@ -40,7 +42,7 @@ func (r *Repository) GetUserAssignmentStats() ([]models.UserStat, error) {
 	return stats, nil
 }
 
-func (r *Repository) GetPRAssignmentStats() ([]models.PRStat, error) {
+func (r *Repository) GetPRAssignmentStats(ctx context.Context) ([]models.PRStat, error) {
 	query := `
 		SELECT 
 			pr.pull_request_id,
@@ -56,7 +58,7 @@ func (r *Repository) GetPRAssignmentStats() ([]models.PRStat, error) {
 		ORDER BY reviewers_count DESC, pr.pull_request_id
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -79,26 +81,32 @@ func (r *Repository) GetPRAssignmentStats() ([]models.PRStat, error) {
 	return stats, nil
 }
 
-func (r *Repository) GetStatsSummary() (models.StatsSummary, error) {
+func (r *Repository) GetStatsSummary(ctx context.Context) (models.StatsSummary, error) {
 	var summary models.StatsSummary
 
-	err := r.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&summary.TotalUsers)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&summary.TotalUsers)
 	if err != nil {
 		return summary, err
 	}
 
-	err = r.db.QueryRow("SELECT COUNT(*) FROM pull_requests").Scan(&summary.TotalPRs)
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pull_requests").Scan(&summary.TotalPRs)
 	if err != nil {
 		return summary, err
 	}
 
-	err = r.db.QueryRow("SELECT COUNT(*) FROM pr_reviewers").Scan(&summary.TotalAssignments)
+	summary.PRsByStatus, err = r.getPRCountsByStatus(ctx)
 	if err != nil {
 		return summary, err
 	}
 
-	if summary.TotalPRs > 0 {
-		summary.AvgReviewersPerPR = float64(summary.TotalAssignments) / float64(summary.TotalPRs)
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pr_reviewers").Scan(&summary.TotalAssignments)
+	if err != nil {
+		return summary, err
+	}
+
+	reviewablePRs := summary.TotalPRs - summary.PRsByStatus[models.PRStatusDraft]
+	if reviewablePRs > 0 {
+		summary.AvgReviewersPerPR = float64(summary.TotalAssignments) / float64(reviewablePRs)
 	}
 
 	query := `
@@ -110,7 +118,7 @@ func (r *Repository) GetStatsSummary() (models.StatsSummary, error) {
 		LIMIT 1
 	`
 	var mostActiveUser sql.NullString
-	err = r.db.QueryRow(query).Scan(&mostActiveUser)
+	err = r.db.QueryRowContext(ctx, query).Scan(&mostActiveUser)
 	if err != nil && err != sql.ErrNoRows {
 		return summary, err
 	}
@@ -126,7 +134,7 @@ func (r *Repository) GetStatsSummary() (models.StatsSummary, error) {
 		LIMIT 1
 	`
 	var mostReviewedPR sql.NullString
-	err = r.db.QueryRow(query).Scan(&mostReviewedPR)
+	err = r.db.QueryRowContext(ctx, query).Scan(&mostReviewedPR)
 	if err != nil && err != sql.ErrNoRows {
 		return summary, err
 	}
@@ -136,3 +144,27 @@ func (r *Repository) GetStatsSummary() (models.StatsSummary, error) {
 
 	return summary, nil
 }
+
+func (r *Repository) getPRCountsByStatus(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT status, COUNT(*) FROM pull_requests GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{
+		models.PRStatusDraft:  0,
+		models.PRStatusOpen:   0,
+		models.PRStatusMerged: 0,
+		models.PRStatusClosed: 0,
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// Tx is a transaction opened by BeginTx. Methods with the InTx suffix only accept
+// transactions opened by the same store.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+type TxBeginner interface {
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+type TeamStore interface {
+	CreateTeam(ctx context.Context, team *models.Team) error
+	CreateTeamInTx(ctx context.Context, tx Tx, team *models.Team) error
+	UpdateTeamSettingsInTx(ctx context.Context, tx Tx, team *models.Team) error
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	TeamExistsInTx(ctx context.Context, tx Tx, teamName string) (bool, error)
+	GetTeamRow(ctx context.Context, teamName string) (*models.Team, error)
+	LockTeamInTx(ctx context.Context, tx Tx, teamName string) (*models.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.Team, error)
+	SetTeamFallbacksInTx(ctx context.Context, tx Tx, teamName string, fallbacks []string) error
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	AddTeamMemberInTx(ctx context.Context, tx Tx, user *models.User) (bool, error)
+	MoveUserInTx(ctx context.Context, tx Tx, userID, teamName string) error
+	DetachUserInTx(ctx context.Context, tx Tx, userID string) error
+	RenameTeamInTx(ctx context.Context, tx Tx, teamName, newName string) error
+	DeleteTeam(ctx context.Context, teamName string) (bool, error)
+	GetTeamAncestors(ctx context.Context, teamName string) ([]string, error)
+	GetTeamAncestorsInTx(ctx context.Context, tx Tx, teamName string) ([]string, error)
+	GetTeamNames(ctx context.Context) ([]string, error)
+	GetSubTeams(ctx context.Context, teamName string) ([]string, error)
+	GetTeamParents(ctx context.Context) (map[string]string, error)
+}
+
+type UserStore interface {
+	CreateUser(ctx context.Context, user *models.User) error
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	LockUserInTx(ctx context.Context, tx Tx, userID string) (*models.User, error)
+	UpdateUsernameInTx(ctx context.Context, tx Tx, userID, username string) error
+	UpdateUserActive(ctx context.Context, userID string, isActive bool) error
+	UpdateUserActiveInTx(ctx context.Context, tx Tx, userID string, isActive bool) error
+	UpdateUserReviewLimit(ctx context.Context, userID string, maxOpenReviews *int) error
+	GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	GetActiveUsersByTeamInTx(ctx context.Context, tx Tx, teamName string) ([]models.User, error)
+	IsUserInOtherTeam(ctx context.Context, userID, teamName string) (bool, error)
+	GetReviewCandidates(ctx context.Context, teamName string) ([]models.ReviewCandidate, error)
+	GetReviewCandidatesInTx(ctx context.Context, tx Tx, teamName string) ([]models.ReviewCandidate, error)
+}
+
+type AbsenceStore interface {
+	CreateAbsence(ctx context.Context, absence *models.Absence) error
+	GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error)
+	DeleteAbsence(ctx context.Context, id int64) (bool, error)
+	LockStartedAbsencesInTx(ctx context.Context, tx Tx, now time.Time, limit int) ([]models.Absence, error)
+	MarkAbsenceReassignedInTx(ctx context.Context, tx Tx, id int64, at time.Time) error
+}
+
+type PullRequestStore interface {
+	CreatePR(ctx context.Context, pr *models.PullRequest) error
+	CreatePRInTx(ctx context.Context, tx Tx, pr *models.PullRequest) error
+	GetPR(ctx context.Context, prID string) (*models.PullRequest, error)
+	GetPRInTx(ctx context.Context, tx Tx, prID string) (*models.PullRequest, error)
+	GetPRReviewers(ctx context.Context, prID string) ([]string, error)
+	GetPRReviewersInTx(ctx context.Context, tx Tx, prID string) ([]string, error)
+	LockPRInTx(ctx context.Context, tx Tx, prID string) (string, error)
+	UpdatePRStatusInTx(ctx context.Context, tx Tx, prID, status string) error
+	PRExists(ctx context.Context, prID string) (bool, error)
+	UpdatePRReviewersInTx(ctx context.Context, tx Tx, prID string, reviewers []string, fallbackTeams map[string]string) error
+	GetPRsByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	GetAllOpenPRs(ctx context.Context) ([]*models.PullRequest, error)
+	CreateMergeOverrideInTx(ctx context.Context, tx Tx, override *models.MergeOverride) error
+	GetMergeOverrides(ctx context.Context, prID string) ([]models.MergeOverride, error)
+}
+
+type ReviewStore interface {
+	CreateReviewInTx(ctx context.Context, tx Tx, review *models.Review) error
+	GetPRReviews(ctx context.Context, prID string) ([]models.Review, error)
+	GetStaleReviews(ctx context.Context, teamName string) ([]models.StaleReview, error)
+	RecordReviewReminder(ctx context.Context, review *models.StaleReview) (bool, error)
+	RecordEscalationAttempt(ctx context.Context, review *models.StaleReview) error
+}
+
+type AssignmentStore interface {
+	CreateAssignmentEventsInTx(ctx context.Context, tx Tx, events []models.AssignmentEvent) error
+	GetAssignmentEvents(ctx context.Context, prID, userID string) ([]models.AssignmentEvent, error)
+}
+
+type OwnershipStore interface {
+	ReplaceOwnershipRules(ctx context.Context, rules []models.OwnershipRule) error
+	GetOwnershipRules(ctx context.Context) ([]models.OwnershipRule, error)
+}
+
+type ExternalAccountStore interface {
+	SetExternalAccount(ctx context.Context, account *models.ExternalAccount) error
+	GetExternalAccountUserID(ctx context.Context, provider, login string) (string, error)
+	GetExternalAccounts(ctx context.Context, provider string) ([]models.ExternalAccount, error)
+	DeleteExternalAccount(ctx context.Context, provider, login string) (bool, error)
+}
+
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhook(ctx context.Context, id int) (*models.Webhook, error)
+	GetWebhooksByTeam(ctx context.Context, teamName string) ([]models.Webhook, error)
+	GetActiveWebhooksByTeam(ctx context.Context, teamName string) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) (bool, error)
+	CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (bool, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, webhookID int, status string) ([]models.WebhookDelivery, error)
+	GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	ClaimWebhookDelivery(ctx context.Context, id int64, now, leaseUntil time.Time) (bool, error)
+	UpdateWebhookDeliveryResult(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type OutboxStore interface {
+	ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxEvent, error)
+	UpdateOutboxEventResult(ctx context.Context, event *models.OutboxEvent) error
+}
+
+// IdempotencyStore keeps the responses of requests sent with an Idempotency-Key header.
+type IdempotencyStore interface {
+	ClaimIdempotencyKey(ctx context.Context, key *models.IdempotencyKey, now time.Time) (*models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key, endpoint string, statusCode int, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key, endpoint string) error
+}
+
+type StatsStore interface {
+	GetUserAssignmentStats(ctx context.Context) ([]models.UserStat, error)
+	GetPRAssignmentStats(ctx context.Context) ([]models.PRStat, error)
+	GetStatsSummary(ctx context.Context) (models.StatsSummary, error)
+}
+
+// Store is the whole data layer the service depends on. Repository implements it on
+// Postgres and memory.Store keeps everything in process.
+type Store interface {
+	TxBeginner
+	TeamStore
+	UserStore
+	AbsenceStore
+	PullRequestStore
+	ReviewStore
+	AssignmentStore
+	OwnershipStore
+	ExternalAccountStore
+	WebhookStore
+	OutboxStore
+	IdempotencyStore
+	StatsStore
+}
@@ -1,37 +1,192 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/lypolix/avito_test/internal/models"
 )
 
-func (r *Repository) CreateTeam(teamName string) error {
-	query := `INSERT INTO teams (team_name) VALUES ($1)`
-	_, err := r.db.Exec(query, teamName)
+func (r *Repository) CreateTeam(ctx context.Context, team *models.Team) error {
+	return r.createTeam(ctx, r.db, team)
+}
+
+func (r *Repository) CreateTeamInTx(ctx context.Context, tx Tx, team *models.Team) error {
+	return r.createTeam(ctx, sqlTx(tx), team)
+}
+
+func (r *Repository) createTeam(ctx context.Context, q queryer, team *models.Team) error {
+	query := `INSERT INTO teams (team_name, reviewer_strategy, min_reviewers, max_reviewers, 
+	          required_approvals, block_on_changes_requested, require_owner_approval, 
+	          review_sla_minutes, review_escalation_minutes, parent_team, inherit_policy) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := q.ExecContext(ctx, query, team.TeamName, team.ReviewerStrategy, team.MinReviewers, team.MaxReviewers,
+		team.RequiredApprovals, team.BlockOnChangesRequested, team.RequireOwnerApproval,
+		team.ReviewSLAMinutes, team.ReviewEscalationMinutes, nullString(team.ParentTeam), team.InheritPolicy)
+	return r.uniqueViolation(err)
+}
+
+func (r *Repository) UpdateTeamSettingsInTx(ctx context.Context, tx Tx, team *models.Team) error {
+	query := `UPDATE teams SET reviewer_strategy = $1, min_reviewers = $2, max_reviewers = $3, 
+	          required_approvals = $4, block_on_changes_requested = $5, require_owner_approval = $6, 
+	          review_sla_minutes = $7, review_escalation_minutes = $8, parent_team = $9, inherit_policy = $10 
+	          WHERE team_name = $11`
+	_, err := sqlTx(tx).ExecContext(ctx, query, team.ReviewerStrategy, team.MinReviewers, team.MaxReviewers,
+		team.RequiredApprovals, team.BlockOnChangesRequested, team.RequireOwnerApproval,
+		team.ReviewSLAMinutes, team.ReviewEscalationMinutes, nullString(team.ParentTeam), team.InheritPolicy, team.TeamName)
 	return err
 }
 
-func (r *Repository) TeamExists(teamName string) (bool, error) {
+func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	return teamExists(ctx, r.db, teamName)
+}
+
+func (r *Repository) TeamExistsInTx(ctx context.Context, tx Tx, teamName string) (bool, error) {
+	return teamExists(ctx, sqlTx(tx), teamName)
+}
+
+func teamExists(ctx context.Context, q queryer, teamName string) (bool, error) {
 	query := `SELECT 1 FROM teams WHERE team_name = $1`
 	var exists int
-	err := r.db.QueryRow(query, teamName).Scan(&exists)
+	err := q.QueryRowContext(ctx, query, teamName).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
 	return exists == 1, err
 }
 
-func (r *Repository) GetTeam(teamName string) (*models.Team, error) {
-	exists, err := r.TeamExists(teamName)
+// maxTeamDepth bounds walks over the team hierarchy.
+const maxTeamDepth = 32
+
+const teamRowQuery = `SELECT team_name, parent_team, inherit_policy, reviewer_strategy, min_reviewers, max_reviewers, 
+	          required_approvals, block_on_changes_requested, require_owner_approval, 
+	          review_sla_minutes, review_escalation_minutes 
+	          FROM teams WHERE team_name = $1`
+
+func (r *Repository) getTeamRow(ctx context.Context, teamName string) (*models.Team, error) {
+	return scanTeamRow(r.db.QueryRowContext(ctx, teamRowQuery, teamName))
+}
+
+func scanTeamRow(row *sql.Row) (*models.Team, error) {
+	var team models.Team
+	var parentTeam sql.NullString
+	err := row.Scan(&team.TeamName, &parentTeam, &team.InheritPolicy,
+		&team.ReviewerStrategy, &team.MinReviewers, &team.MaxReviewers,
+		&team.RequiredApprovals, &team.BlockOnChangesRequested, &team.RequireOwnerApproval,
+		&team.ReviewSLAMinutes, &team.ReviewEscalationMinutes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
+	team.ParentTeam = parentTeam.String
+	return &team, nil
+}
+
+// GetTeamRow returns the settings stored for the team itself, without inheritance,
+// ancestors or fallbacks. Updates start from it so inherited values are not copied in.
+func (r *Repository) GetTeamRow(ctx context.Context, teamName string) (*models.Team, error) {
+	return r.getTeamRow(ctx, teamName)
+}
+
+// LockTeamInTx returns the stored row of the team like GetTeamRow and locks it until the
+// transaction ends, or returns nil if the team does not exist. Changes to the hierarchy
+// lock every team they touch, so concurrent updates cannot join into a cycle.
+func (r *Repository) LockTeamInTx(ctx context.Context, tx Tx, teamName string) (*models.Team, error) {
+	return scanTeamRow(sqlTx(tx).QueryRowContext(ctx, teamRowQuery+` `+r.dialect.forUpdate, teamName))
+}
+
+// GetTeamSettings returns the effective settings of the team: a team that inherits its
+// policy gets it from the nearest ancestor that does not. Ancestors are listed nearest first.
+func (r *Repository) GetTeamSettings(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := r.getTeamRow(ctx, teamName)
+	if err != nil || team == nil {
+		return nil, err
+	}
+
+	ancestors, err := r.GetTeamAncestors(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	team.Ancestors = ancestors
+
+	if team.InheritPolicy {
+		for _, ancestorName := range ancestors {
+			ancestor, err := r.getTeamRow(ctx, ancestorName)
+			if err != nil {
+				return nil, err
+			}
+			if ancestor == nil {
+				break
+			}
+			team.CopyPolicy(ancestor)
+			if !ancestor.InheritPolicy {
+				break
+			}
+		}
+	}
+
+	fallbacks, err := r.GetTeamFallbacks(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	team.FallbackTeams = fallbacks
+
+	return team, nil
+}
+
+func (r *Repository) GetTeamFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	query := `SELECT fallback_team_name FROM team_fallbacks WHERE team_name = $1 ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fallbacks := []string{}
+	for rows.Next() {
+		var fallback string
+		if err := rows.Scan(&fallback); err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, fallback)
+	}
+	return fallbacks, rows.Err()
+}
+
+func (r *Repository) SetTeamFallbacksInTx(ctx context.Context, tx Tx, teamName string, fallbacks []string) error {
+	return setTeamFallbacks(ctx, sqlTx(tx), teamName, fallbacks)
+}
+
+func setTeamFallbacks(ctx context.Context, q queryer, teamName string, fallbacks []string) error {
+	_, err := q.ExecContext(ctx, "DELETE FROM team_fallbacks WHERE team_name = $1", teamName)
+	if err != nil {
+		return err
+	}
+
+	for i, fallback := range fallbacks {
+		_, err = q.ExecContext(ctx,
+			"INSERT INTO team_fallbacks (team_name, fallback_team_name, position) VALUES ($1, $2, $3)",
+			teamName, fallback, i,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := r.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
 		return nil, nil
 	}
 
-	users, err := r.GetUsersByTeam(teamName)
+	users, err := r.GetUsersByTeam(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -39,14 +194,131 @@ func (r *Repository) GetTeam(teamName string) (*models.Team, error) {
 	var members []models.TeamMember
 	for _, user := range users {
 		members = append(members, models.TeamMember{
-			UserID:   user.UserID,
-			Username: user.Username,
-			IsActive: user.IsActive,
+			UserID:         user.UserID,
+			Username:       user.Username,
+			IsActive:       user.IsActive,
+			ReviewWeight:   user.ReviewWeight,
+			MaxOpenReviews: user.MaxOpenReviews,
 		})
 	}
+	team.Members = members
+
+	return team, nil
+}
+
+// AddTeamMemberInTx creates the user in the team, or attaches an existing user who was
+// removed from their previous team. It reports false if the user already belongs to a team.
+func (r *Repository) AddTeamMemberInTx(ctx context.Context, tx Tx, user *models.User) (bool, error) {
+	query := `INSERT INTO users (user_id, username, team_name, is_active, review_weight, max_open_reviews) 
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (user_id) DO UPDATE SET username = EXCLUDED.username, team_name = EXCLUDED.team_name,
+	              is_active = EXCLUDED.is_active, review_weight = EXCLUDED.review_weight,
+	              max_open_reviews = EXCLUDED.max_open_reviews
+	          WHERE users.team_name IS NULL`
+	result, err := sqlTx(tx).ExecContext(ctx, query, user.UserID, user.Username, user.TeamName, user.IsActive, user.ReviewWeight, user.MaxOpenReviews)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *Repository) MoveUserInTx(ctx context.Context, tx Tx, userID, teamName string) error {
+	_, err := sqlTx(tx).ExecContext(ctx, `UPDATE users SET team_name = $1 WHERE user_id = $2`, teamName, userID)
+	return err
+}
+
+func (r *Repository) DetachUserInTx(ctx context.Context, tx Tx, userID string) error {
+	_, err := sqlTx(tx).ExecContext(ctx, `UPDATE users SET team_name = NULL WHERE user_id = $1`, userID)
+	return err
+}
+
+// RenameTeamInTx relies on ON UPDATE CASCADE to carry the new name over to members,
+// fallbacks, ownership rules and webhooks, and renames the fallback team recorded on
+// reviewer assignments itself. It returns ErrAlreadyExists if newName is taken.
+func (r *Repository) RenameTeamInTx(ctx context.Context, tx Tx, teamName, newName string) error {
+	q := sqlTx(tx)
+	if _, err := q.ExecContext(ctx, `UPDATE teams SET team_name = $1 WHERE team_name = $2`, newName, teamName); err != nil {
+		return r.uniqueViolation(err)
+	}
+	_, err := q.ExecContext(ctx, `UPDATE pr_reviewers SET fallback_team = $1 WHERE fallback_team = $2`, newName, teamName)
+	return err
+}
+
+// DeleteTeam removes the team only if it has neither members nor sub-teams and reports whether it did.
+func (r *Repository) DeleteTeam(ctx context.Context, teamName string) (bool, error) {
+	query := `DELETE FROM teams WHERE team_name = $1 
+	          AND NOT EXISTS (SELECT 1 FROM users WHERE team_name = $1)
+	          AND NOT EXISTS (SELECT 1 FROM teams WHERE parent_team = $1)`
+	result, err := r.db.ExecContext(ctx, query, teamName)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetTeamAncestors returns the parent of the team, its parent and so on up to the root.
+func (r *Repository) GetTeamAncestors(ctx context.Context, teamName string) ([]string, error) {
+	return queryTeamNames(ctx, r.db, teamAncestorsQuery, teamName, maxTeamDepth)
+}
+
+func (r *Repository) GetTeamAncestorsInTx(ctx context.Context, tx Tx, teamName string) ([]string, error) {
+	return queryTeamNames(ctx, sqlTx(tx), teamAncestorsQuery, teamName, maxTeamDepth)
+}
+
+const teamAncestorsQuery = `
+		WITH RECURSIVE ancestors(team_name, parent_team, depth) AS (
+			SELECT team_name, parent_team, 0 FROM teams WHERE team_name = $1
+			UNION ALL
+			SELECT t.team_name, t.parent_team, a.depth + 1
+			FROM teams t JOIN ancestors a ON t.team_name = a.parent_team
+			WHERE a.depth < $2
+		)
+		SELECT team_name FROM ancestors WHERE depth > 0 ORDER BY depth
+	`
+
+func (r *Repository) GetTeamNames(ctx context.Context) ([]string, error) {
+	return queryTeamNames(ctx, r.db, `SELECT team_name FROM teams ORDER BY team_name`)
+}
+
+func (r *Repository) GetSubTeams(ctx context.Context, teamName string) ([]string, error) {
+	return queryTeamNames(ctx, r.db, `SELECT team_name FROM teams WHERE parent_team = $1 ORDER BY team_name`, teamName)
+}
+
+// GetTeamParents maps every team that has a parent to that parent.
+func (r *Repository) GetTeamParents(ctx context.Context) (map[string]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT team_name, parent_team FROM teams WHERE parent_team IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parents := make(map[string]string)
+	for rows.Next() {
+		var teamName, parentTeam string
+		if err := rows.Scan(&teamName, &parentTeam); err != nil {
+			return nil, err
+		}
+		parents[teamName] = parentTeam
+	}
+	return parents, rows.Err()
+}
+
+func queryTeamNames(ctx context.Context, q queryer, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return &models.Team{
-		TeamName: teamName,
-		Members:  members,
-	}, nil
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
 }
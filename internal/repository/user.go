@@ -1,102 +1,145 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/lypolix/avito_test/internal/models"
 )
 
-func (r *Repository) CreateUser(user *models.User) error {
-	query := `INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(query, user.UserID, user.Username, user.TeamName, user.IsActive)
-	return err
+// Users removed from their team keep their row with a NULL team_name, reported as an empty string.
+const userColumns = `user_id, username, COALESCE(team_name, '') AS team_name, is_active, review_weight, max_open_reviews`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-func (r *Repository) GetUser(userID string) (*models.User, error) {
-	query := `SELECT user_id, username, team_name, is_active FROM users WHERE user_id = $1`
+func scanUser(row rowScanner) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRow(query, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
-	if err == sql.ErrNoRows {
-		return nil, nil
+	var maxOpenReviews sql.NullInt64
+	if err := row.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.ReviewWeight, &maxOpenReviews); err != nil {
+		return nil, err
+	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		user.MaxOpenReviews = &limit
+	}
+	return &user, nil
+}
+
+func scanUsers(rows *sql.Rows) ([]models.User, error) {
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
 	}
-	return &user, err
+	return users, rows.Err()
 }
 
-func (r *Repository) UpdateUserActive(userID string, isActive bool) error {
-	query := `UPDATE users SET is_active = $1 WHERE user_id = $2`
-	_, err := r.db.Exec(query, isActive, userID)
+func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
+	query := `INSERT INTO users (user_id, username, team_name, is_active, review_weight, max_open_reviews) 
+	          VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.ExecContext(ctx, query, user.UserID, user.Username, user.TeamName, user.IsActive, user.ReviewWeight, user.MaxOpenReviews)
 	return err
 }
 
-func (r *Repository) UpdateUserActiveInTx(tx *sql.Tx, userID string, isActive bool) error {
-	query := `UPDATE users SET is_active = $1 WHERE user_id = $2`
-	_, err := tx.Exec(query, isActive, userID)
+func (r *Repository) UpdateUsernameInTx(ctx context.Context, tx Tx, userID, username string) error {
+	_, err := sqlTx(tx).ExecContext(ctx, `UPDATE users SET username = $1 WHERE user_id = $2`, username, userID)
 	return err
 }
 
-func (r *Repository) GetActiveUsersByTeam(teamName string) ([]models.User, error) {
-	query := `SELECT user_id, username, team_name, is_active 
-	          FROM users WHERE team_name = $1 AND is_active = true`
-	rows, err := r.db.Query(query, teamName)
-	if err != nil {
-		return nil, err
+func (r *Repository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id = $1`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	defer rows.Close()
+	return user, err
+}
 
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
-			return nil, err
-		}
-		users = append(users, user)
+// LockUserInTx returns the user like GetUser and locks the row until the transaction ends,
+// or returns nil if the user does not exist.
+func (r *Repository) LockUserInTx(ctx context.Context, tx Tx, userID string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id = $1 ` + r.dialect.forUpdate
+	user, err := scanUser(sqlTx(tx).QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	return users, nil
+	return user, err
 }
 
-func (r *Repository) GetUsersByTeam(teamName string) ([]models.User, error) {
-	query := `SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1`
-	rows, err := r.db.Query(query, teamName)
+func (r *Repository) UpdateUserActive(ctx context.Context, userID string, isActive bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
-			return nil, err
-		}
-		users = append(users, user)
+	if err := r.UpdateUserActiveInTx(ctx, tx, userID, isActive); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateUserActiveInTx queues a user.deactivated outbox event when an active user is deactivated.
+func (r *Repository) UpdateUserActiveInTx(ctx context.Context, tx Tx, userID string, isActive bool) error {
+	query := `UPDATE users SET is_active = $1 WHERE user_id = $2 AND is_active <> $1 RETURNING COALESCE(team_name, '')`
+	var teamName string
+	err := sqlTx(tx).QueryRowContext(ctx, query, isActive, userID).Scan(&teamName)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil || isActive {
+		return err
 	}
-	return users, nil
+
+	data := models.UserDeactivatedData{UserID: userID, TeamName: teamName}
+	return r.insertOutboxEvent(ctx, sqlTx(tx), models.EventUserDeactivated, teamName, data)
 }
 
-func (r *Repository) GetActiveUsersByTeamInTx(tx *sql.Tx, teamName string) ([]models.User, error) {
-	query := `SELECT user_id, username, team_name, is_active 
-	          FROM users WHERE team_name = $1 AND is_active = true`
-	rows, err := tx.Query(query, teamName)
+func (r *Repository) UpdateUserReviewLimit(ctx context.Context, userID string, maxOpenReviews *int) error {
+	query := `UPDATE users SET max_open_reviews = $1 WHERE user_id = $2`
+	_, err := r.db.ExecContext(ctx, query, maxOpenReviews, userID)
+	return err
+}
+
+func (r *Repository) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE team_name = $1 AND is_active = true`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return scanUsers(rows)
+}
 
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
-			return nil, err
-		}
-		users = append(users, user)
+func (r *Repository) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE team_name = $1`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	return scanUsers(rows)
+}
+
+func (r *Repository) GetActiveUsersByTeamInTx(ctx context.Context, tx Tx, teamName string) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE team_name = $1 AND is_active = true`
+	rows, err := sqlTx(tx).QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
 	}
-	return users, nil
+	return scanUsers(rows)
 }
 
-func (r *Repository) IsUserInOtherTeam(userID, teamName string) (bool, error) {
-	query := `SELECT team_name FROM users WHERE user_id = $1`
+func (r *Repository) IsUserInOtherTeam(ctx context.Context, userID, teamName string) (bool, error) {
+	query := `SELECT COALESCE(team_name, '') FROM users WHERE user_id = $1`
 	var existingTeamName string
-	err := r.db.QueryRow(query, userID).Scan(&existingTeamName)
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&existingTeamName)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
@@ -104,5 +147,55 @@ func (r *Repository) IsUserInOtherTeam(userID, teamName string) (bool, error) {
 		return false, err
 	}
 
-	return existingTeamName != teamName, nil
+	return existingTeamName != "" && existingTeamName != teamName, nil
+}
+
+func (r *Repository) GetReviewCandidates(ctx context.Context, teamName string) ([]models.ReviewCandidate, error) {
+	return r.getReviewCandidates(ctx, r.db, teamName)
+}
+
+func (r *Repository) GetReviewCandidatesInTx(ctx context.Context, tx Tx, teamName string) ([]models.ReviewCandidate, error) {
+	return r.getReviewCandidates(ctx, sqlTx(tx), teamName)
+}
+
+// getReviewCandidates returns active members of the team who are not inside an absence
+// period right now, with their open review count and their latest assignment event.
+// Members of sub-teams are not candidates: the search widens upwards only, see teamTiers.
+func (r *Repository) getReviewCandidates(ctx context.Context, q queryer, teamName string) ([]models.ReviewCandidate, error) {
+	query := `
+		SELECT u.user_id, u.review_weight, u.max_open_reviews, COUNT(pr.pull_request_id) AS open_reviews,
+		       (SELECT COALESCE(MAX(ae.id), 0) FROM assignment_events ae
+		        WHERE ae.user_id = u.user_id AND ae.action IN ('ASSIGNED', 'REPLACED')) AS last_assignment_id
+		FROM users u
+		LEFT JOIN pr_reviewers prr ON u.user_id = prr.user_id
+		LEFT JOIN pull_requests pr ON prr.pr_id = pr.pull_request_id AND pr.status = 'OPEN'
+		WHERE u.team_name = $1 AND u.is_active = true
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_absences ua
+		      WHERE ua.user_id = u.user_id AND ua.starts_at <= ` + r.dialect.now + ` AND ua.ends_at > ` + r.dialect.now + `
+		  )
+		GROUP BY u.user_id, u.review_weight, u.max_open_reviews
+		ORDER BY u.user_id
+	`
+	rows, err := q.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []models.ReviewCandidate
+	for rows.Next() {
+		var candidate models.ReviewCandidate
+		var maxOpenReviews sql.NullInt64
+		if err := rows.Scan(&candidate.UserID, &candidate.Weight, &maxOpenReviews, &candidate.OpenReviews,
+			&candidate.LastAssignmentID); err != nil {
+			return nil, err
+		}
+		if maxOpenReviews.Valid {
+			limit := int(maxOpenReviews.Int64)
+			candidate.MaxOpenReviews = &limit
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
 }
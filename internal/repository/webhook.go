@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+const webhookColumns = `id, team_name, url, secret, event_types, is_active, created_at`
+
+const deliveryColumns = `id, webhook_id, event_type, payload, status, attempts, response_code, last_error, 
+	next_attempt_at, replay_of, outbox_event_id, created_at, delivered_at`
+
+func (r *Repository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `INSERT INTO webhooks (team_name, url, secret, event_types, is_active) 
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, webhook.TeamName, webhook.URL, webhook.Secret,
+		strings.Join(webhook.EventTypes, ","), webhook.IsActive).Scan(&webhook.ID, &webhook.CreatedAt)
+}
+
+func (r *Repository) GetWebhook(ctx context.Context, id int) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return webhook, err
+}
+
+func (r *Repository) GetWebhooksByTeam(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE team_name = $1 ORDER BY id`
+	return r.queryWebhooks(ctx, query, teamName)
+}
+
+func (r *Repository) GetActiveWebhooksByTeam(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE team_name = $1 AND is_active ORDER BY id`
+	return r.queryWebhooks(ctx, query, teamName)
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// CreateWebhookDelivery reports false when a delivery of the same outbox event to the
+// same webhook already exists, which happens when the outbox dispatcher retries an event.
+func (r *Repository) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_type, payload, status, next_attempt_at, replay_of, outbox_event_id) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (webhook_id, outbox_event_id) DO NOTHING 
+	          RETURNING id, created_at`
+	var replayOf, outboxEventID interface{}
+	if delivery.ReplayOf != nil {
+		replayOf = *delivery.ReplayOf
+	}
+	if delivery.OutboxEventID != nil {
+		outboxEventID = *delivery.OutboxEventID
+	}
+	err := r.db.QueryRowContext(ctx, query, delivery.WebhookID, delivery.EventType, string(delivery.Payload),
+		delivery.Status, delivery.NextAttemptAt, replayOf, outboxEventID).Scan(&delivery.ID, &delivery.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *Repository) GetWebhookDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return delivery, err
+}
+
+func (r *Repository) GetWebhookDeliveries(ctx context.Context, webhookID int, status string) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries 
+	          WHERE webhook_id = $1 AND ($2 = '' OR status = $2) ORDER BY id`
+	return r.queryDeliveries(ctx, query, webhookID, status)
+}
+
+func (r *Repository) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries 
+	          WHERE status = 'PENDING' AND next_attempt_at <= $1 ORDER BY next_attempt_at, id LIMIT $2`
+	return r.queryDeliveries(ctx, query, now, limit)
+}
+
+// ClaimWebhookDelivery pushes next_attempt_at forward so that other workers skip
+// the delivery while it is being sent. It reports false if someone else claimed it first.
+func (r *Repository) ClaimWebhookDelivery(ctx context.Context, id int64, now, leaseUntil time.Time) (bool, error) {
+	query := `UPDATE webhook_deliveries SET next_attempt_at = $1 
+	          WHERE id = $2 AND status = 'PENDING' AND next_attempt_at <= $3`
+	result, err := r.db.ExecContext(ctx, query, leaseUntil, id, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *Repository) UpdateWebhookDeliveryResult(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries 
+	          SET status = $1, attempts = $2, response_code = $3, last_error = $4, next_attempt_at = $5, delivered_at = $6 
+	          WHERE id = $7`
+	var responseCode, deliveredAt interface{}
+	if delivery.ResponseCode != nil {
+		responseCode = *delivery.ResponseCode
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt = *delivery.DeliveredAt
+	}
+	_, err := r.db.ExecContext(ctx, query, delivery.Status, delivery.Attempts, responseCode, nullString(delivery.LastError),
+		delivery.NextAttemptAt, deliveredAt, delivery.ID)
+	return err
+}
+
+func (r *Repository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *Repository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var eventTypes string
+	if err := row.Scan(&webhook.ID, &webhook.TeamName, &webhook.URL, &webhook.Secret, &eventTypes,
+		&webhook.IsActive, &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	webhook.EventTypes = []string{}
+	if eventTypes != "" {
+		webhook.EventTypes = strings.Split(eventTypes, ",")
+	}
+	return &webhook, nil
+}
+
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+	var responseCode sql.NullInt64
+	var lastError sql.NullString
+	var replayOf, outboxEventID sql.NullInt64
+	var deliveredAt sql.NullTime
+	if err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventType, &payload, &delivery.Status,
+		&delivery.Attempts, &responseCode, &lastError, &delivery.NextAttemptAt, &replayOf, &outboxEventID,
+		&delivery.CreatedAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	delivery.Payload = []byte(payload)
+	delivery.LastError = lastError.String
+	if responseCode.Valid {
+		code := int(responseCode.Int64)
+		delivery.ResponseCode = &code
+	}
+	if replayOf.Valid {
+		delivery.ReplayOf = &replayOf.Int64
+	}
+	if outboxEventID.Valid {
+		delivery.OutboxEventID = &outboxEventID.Int64
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return &delivery, nil
+}
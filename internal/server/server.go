@@ -14,7 +14,7 @@ type Server struct {
 }
 
 func New(serverConfig config.ServerConfig) *Server {
-	router := gin.Default()
+	router := gin.New()
 
 	return &Server{
 		httpServer: &http.Server{
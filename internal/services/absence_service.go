@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Service) AddAbsence(ctx context.Context, req *models.AddAbsenceRequest) (*models.Absence, error) {
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, NewBusinessError(ErrorInvalidAbsence, "ends_at must be after starts_at")
+	}
+	if !req.EndsAt.After(time.Now()) {
+		return nil, NewBusinessError(ErrorInvalidAbsence, "ends_at must be in the future")
+	}
+
+	user, err := s.repo.GetUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	absence := &models.Absence{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt.UTC(),
+		EndsAt:   req.EndsAt.UTC(),
+		Reason:   req.Reason,
+	}
+	if err := s.repo.CreateAbsence(ctx, absence); err != nil {
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+func (s *Service) GetAbsences(ctx context.Context, userID string) (*models.AbsencesResponse, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	absences, err := s.repo.GetAbsencesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AbsencesResponse{
+		UserID:   userID,
+		Absences: absences,
+	}, nil
+}
+
+func (s *Service) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	deleted, err := s.repo.DeleteAbsence(ctx, absenceID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return NewBusinessError(ErrorNotFound, "absence not found")
+	}
+	return nil
+}
+
+// ReassignAbsentReviewers hands the open reviews of users whose absence has begun over to
+// other candidates. Each absence is processed once, even if it later gets extended.
+func (s *Service) ReassignAbsentReviewers(ctx context.Context, limit int) ([]models.AbsenceReassignment, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	absences, err := s.repo.LockStartedAbsencesInTx(ctx, tx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.AbsenceReassignment, 0, len(absences))
+	for _, absence := range absences {
+		result, err := s.reassignAbsentReviewerInTx(ctx, tx, absence)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.MarkAbsenceReassignedInTx(ctx, tx, absence.ID, now); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *Service) reassignAbsentReviewerInTx(ctx context.Context, tx repository.Tx, absence models.Absence) (models.AbsenceReassignment, error) {
+	result := models.AbsenceReassignment{
+		AbsenceID:           absence.ID,
+		UserID:              absence.UserID,
+		ReassignedPRs:       []models.ReassignedPRDetail{},
+		FailedReassignments: []models.FailedReassignment{},
+	}
+
+	user, err := s.repo.GetUser(ctx, absence.UserID)
+	if err != nil || user == nil {
+		return result, err
+	}
+
+	team, err := s.repo.GetTeamSettings(ctx, user.TeamName)
+	if err != nil || team == nil {
+		return result, err
+	}
+
+	result.ReassignedPRs, result.FailedReassignments, err = s.reassignReviewsOfUsersInTx(ctx, tx, team, []string{user.UserID},
+		models.OperationAbsence, "reviewer is absent")
+	return result, err
+}
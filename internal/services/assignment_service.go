@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Service) GetAssignmentEvents(ctx context.Context, prID, userID string) (*models.AssignmentEventsResponse, error) {
+	events, err := s.repo.GetAssignmentEvents(ctx, prID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AssignmentEventsResponse{Events: events}, nil
+}
+
+func assignedEvents(prID, operation string, reviewers []selectedReviewer) []models.AssignmentEvent {
+	events := make([]models.AssignmentEvent, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		events = append(events, models.AssignmentEvent{
+			PullRequestID: prID,
+			Action:        models.AssignmentAssigned,
+			UserID:        reviewer.UserID,
+			Operation:     operation,
+			Reason:        selectionReason(reviewer),
+		})
+	}
+	return events
+}
+
+func replacedEvent(prID, operation, oldUserID string, newReviewer selectedReviewer, reason string) models.AssignmentEvent {
+	if newReviewer.FallbackTeam != "" {
+		reason += ", " + selectionReason(newReviewer)
+	}
+	return models.AssignmentEvent{
+		PullRequestID:  prID,
+		Action:         models.AssignmentReplaced,
+		UserID:         newReviewer.UserID,
+		PreviousUserID: oldUserID,
+		Operation:      operation,
+		Reason:         reason,
+	}
+}
+
+func removedEvent(prID, operation, userID, reason string) models.AssignmentEvent {
+	return models.AssignmentEvent{
+		PullRequestID: prID,
+		Action:        models.AssignmentRemoved,
+		UserID:        userID,
+		Operation:     operation,
+		Reason:        reason,
+	}
+}
+
+func selectionReason(reviewer selectedReviewer) string {
+	switch {
+	case reviewer.Owner:
+		return "code owner of changed files"
+	case reviewer.FallbackTeam != "":
+		return "drawn from fallback team " + reviewer.FallbackTeam
+	default:
+		return "selected by team strategy"
+	}
+}
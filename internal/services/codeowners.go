@@ -0,0 +1,107 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type codeownersLine struct {
+	Number  int
+	Pattern string
+	Owners  []string
+}
+
+func parseCodeowners(content string) ([]codeownersLine, error) {
+	var lines []codeownersLine
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	number := 0
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if _, err := compileOwnershipPattern(fields[0]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q", number, fields[0])
+		}
+
+		lines = append(lines, codeownersLine{
+			Number:  number,
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		})
+	}
+
+	return lines, scanner.Err()
+}
+
+// matchOwnershipPattern follows CODEOWNERS rules: patterns without an inner slash
+// match at any depth, a trailing slash matches directories only, a trailing single
+// "*" does not descend into subdirectories, and any other pattern matching a
+// directory also matches everything below it.
+func matchOwnershipPattern(pattern, path string) bool {
+	re, err := compileOwnershipPattern(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+func compileOwnershipPattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimPrefix(pattern, "/")
+	anchored := p != pattern
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	if strings.Contains(p, "/") {
+		anchored = true
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(p[i:], "**"):
+			expr.WriteString(".*")
+			i += 2
+		case p[i] == '*':
+			expr.WriteString("[^/]*")
+			i++
+		case p[i] == '?':
+			expr.WriteString("[^/]")
+			i++
+		default:
+			_, size := utf8.DecodeRuneInString(p[i:])
+			expr.WriteString(regexp.QuoteMeta(p[i : i+size]))
+			i += size
+		}
+	}
+
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case strings.HasSuffix(p, "*") && !strings.HasSuffix(p, "**"):
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(expr.String())
+}
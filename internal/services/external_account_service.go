@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// Logins are stored lowercased because both GitHub and GitLab treat them case-insensitively.
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+func (s *Service) SetExternalAccount(ctx context.Context, provider, login, userID string) (*models.ExternalAccount, error) {
+	login = normalizeLogin(login)
+	if login == "" {
+		return nil, NewBusinessError(ErrorInvalidAccount, "login must not be empty")
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, NewBusinessError(ErrorNotFound, "user not found")
+	}
+
+	account := &models.ExternalAccount{Provider: provider, Login: login, UserID: userID}
+	if err := s.repo.SetExternalAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *Service) GetExternalAccounts(ctx context.Context, provider string) (*models.ExternalAccountsResponse, error) {
+	accounts, err := s.repo.GetExternalAccounts(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ExternalAccountsResponse{Provider: provider, Accounts: accounts}, nil
+}
+
+func (s *Service) DeleteExternalAccount(ctx context.Context, provider, login string) error {
+	deleted, err := s.repo.DeleteExternalAccount(ctx, provider, normalizeLogin(login))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return NewBusinessError(ErrorNotFound, "account not found")
+	}
+	return nil
+}
+
+func (s *Service) resolveExternalUser(ctx context.Context, provider, login string) (string, error) {
+	userID, err := s.repo.GetExternalAccountUserID(ctx, provider, normalizeLogin(login))
+	if err != nil {
+		return "", err
+	}
+	if userID == "" {
+		return "", NewBusinessError(ErrorUnknownAccount, "no user is mapped to "+provider+" login "+login)
+	}
+	return userID, nil
+}
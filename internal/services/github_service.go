@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+const (
+	githubMergeActor  = "github"
+	githubMergeReason = "merged on GitHub"
+)
+
+// GitHubPullRequestID builds the pull_request_id used for GitHub PRs, e.g. "octo-org/api#42".
+func GitHubPullRequestID(repository string, number int) string {
+	return fmt.Sprintf("%s#%d", repository, number)
+}
+
+// HandleGitHubPullRequest applies a pull_request webhook event. Redelivered events are
+// harmless: every transition it triggers is idempotent and an already known PR is not recreated.
+func (s *Service) HandleGitHubPullRequest(ctx context.Context, event *models.GitHubPullRequestEvent) (*models.IntegrationEventResponse, error) {
+	if event.Repository.FullName == "" || event.PullRequest.Number == 0 {
+		return nil, NewBusinessError(ErrorInvalidPayload, "repository.full_name and pull_request.number are required")
+	}
+	prID := GitHubPullRequestID(event.Repository.FullName, event.PullRequest.Number)
+
+	var (
+		result string
+		pr     *models.PullRequest
+		err    error
+	)
+	switch event.Action {
+	case "opened":
+		result = models.IntegrationCreated
+		pr, err = s.createExternalPR(ctx, models.ProviderGitHub, prID, event.PullRequest.Title,
+			event.PullRequest.User.Login, event.PullRequest.Draft)
+	case "ready_for_review":
+		result = models.IntegrationReady
+		pr, err = s.MarkPRReady(ctx, prID)
+	case "reopened":
+		result = models.IntegrationReopened
+		pr, err = s.ReopenPR(ctx, prID)
+	case "closed":
+		if !event.PullRequest.Merged {
+			result = models.IntegrationClosed
+			pr, err = s.ClosePR(ctx, prID)
+			break
+		}
+		result = models.IntegrationMerged
+		actor := githubMergeActor
+		if event.PullRequest.MergedBy != nil {
+			actor = s.externalActor(ctx, models.ProviderGitHub, event.PullRequest.MergedBy.Login)
+		}
+		pr, err = s.mergeExternalPR(ctx, prID, actor, githubMergeReason)
+	default:
+		return &models.IntegrationEventResponse{Result: models.IntegrationIgnored, PullRequestID: prID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IntegrationEventResponse{Result: result, PullRequestID: prID, PR: pr}, nil
+}
+
+func (s *Service) createExternalPR(ctx context.Context, provider, prID, title, authorLogin string, draft bool) (*models.PullRequest, error) {
+	existing, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	authorID, err := s.resolveExternalUser(ctx, provider, authorLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CreatePR(ctx, &models.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: title,
+		AuthorID:        authorID,
+		Draft:           draft,
+	})
+}
+
+// mergeExternalPR records a merge that already happened on the provider side. If the
+// merge policy is not satisfied here, the merge is stored as an override so it stays audited.
+func (s *Service) mergeExternalPR(ctx context.Context, prID, actor, reason string) (*models.PullRequest, error) {
+	pr, err := s.MergePR(ctx, prID)
+	if bizErr, ok := err.(*BusinessError); ok && bizErr.Code == ErrorMergeBlocked {
+		return s.ForceMergePR(ctx, prID, &models.MergeOverride{Actor: actor, Reason: reason})
+	}
+	return pr, err
+}
+
+// externalActor names the user behind a provider login for audit records, falling back
+// to "<provider>:<login>" when the login is not mapped.
+func (s *Service) externalActor(ctx context.Context, provider, login string) string {
+	userID, err := s.repo.GetExternalAccountUserID(ctx, provider, normalizeLogin(login))
+	if err != nil || userID == "" {
+		return provider + ":" + login
+	}
+	return userID
+}
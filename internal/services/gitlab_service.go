@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+const gitlabMergeReason = "merged on GitLab"
+
+// GitLabPullRequestID builds the pull_request_id used for GitLab merge requests, e.g. "group/api!7".
+func GitLabPullRequestID(projectPath string, iid int) string {
+	return fmt.Sprintf("%s!%d", projectPath, iid)
+}
+
+// HandleGitLabMergeRequest applies a Merge Request Hook event. GitLab sends the numeric
+// author id only, so the user who triggered the event is taken as the author on "open".
+func (s *Service) HandleGitLabMergeRequest(ctx context.Context, event *models.GitLabMergeRequestEvent) (*models.IntegrationEventResponse, error) {
+	mr := event.ObjectAttributes
+	if event.Project.PathWithNamespace == "" || mr.IID == 0 {
+		return nil, NewBusinessError(ErrorInvalidPayload, "project.path_with_namespace and object_attributes.iid are required")
+	}
+	prID := GitLabPullRequestID(event.Project.PathWithNamespace, mr.IID)
+
+	var (
+		result string
+		pr     *models.PullRequest
+		err    error
+	)
+	switch {
+	case mr.Action == "open":
+		result = models.IntegrationCreated
+		pr, err = s.createExternalPR(ctx, models.ProviderGitLab, prID, mr.Title, event.User.Username,
+			mr.Draft || mr.WorkInProgress)
+	case mr.Action == "update" && event.Changes.Draft != nil && event.Changes.Draft.Previous && !event.Changes.Draft.Current:
+		result = models.IntegrationReady
+		pr, err = s.MarkPRReady(ctx, prID)
+	case mr.Action == "reopen":
+		result = models.IntegrationReopened
+		pr, err = s.ReopenPR(ctx, prID)
+	case mr.Action == "close":
+		result = models.IntegrationClosed
+		pr, err = s.ClosePR(ctx, prID)
+	case mr.Action == "merge":
+		result = models.IntegrationMerged
+		pr, err = s.mergeExternalPR(ctx, prID, s.externalActor(ctx, models.ProviderGitLab, event.User.Username), gitlabMergeReason)
+	default:
+		return &models.IntegrationEventResponse{Result: models.IntegrationIgnored, PullRequestID: prID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IntegrationEventResponse{Result: result, PullRequestID: prID, PR: pr}, nil
+}
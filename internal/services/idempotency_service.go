@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+// maxIdempotencyKeyLength matches the idempotency_keys.idempotency_key column.
+const maxIdempotencyKeyLength = 255
+
+// ClaimIdempotencyKey reserves key for the request or returns the response stored for an
+// earlier request with the same key. A nil result means the caller handles the request and
+// then completes or releases the key.
+func (s *Service) ClaimIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	if len(key.Key) > maxIdempotencyKeyLength {
+		return nil, NewBusinessError(ErrorInvalidIdempotencyKey, "Idempotency-Key must not be longer than 255 characters")
+	}
+
+	stored, err := s.repo.ClaimIdempotencyKey(ctx, key, time.Now().UTC())
+	if err != nil || stored == nil {
+		return nil, err
+	}
+	if stored.RequestHash != key.RequestHash {
+		return nil, NewBusinessError(ErrorIdempotencyKeyReused, "Idempotency-Key was already used with a different request")
+	}
+	if !stored.Completed() {
+		return nil, NewBusinessError(ErrorRequestInProgress, "a request with this Idempotency-Key is still being processed")
+	}
+	return stored, nil
+}
+
+func (s *Service) CompleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey, statusCode int, response []byte) error {
+	return s.repo.CompleteIdempotencyKey(ctx, key.Key, key.Endpoint, statusCode, response)
+}
+
+// ReleaseIdempotencyKey forgets the key, so a retry is handled as a new request.
+func (s *Service) ReleaseIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error {
+	return s.repo.ReleaseIdempotencyKey(ctx, key.Key, key.Endpoint)
+}
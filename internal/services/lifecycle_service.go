@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/lypolix/avito_test/internal/logging"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Service) MarkPRReady(ctx context.Context, prID string) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pr_id", prID)
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	pr, err := s.lockPR(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pr.Status {
+	case models.PRStatusOpen:
+		return pr, nil
+	case models.PRStatusDraft:
+	default:
+		return nil, NewBusinessError(ErrorInvalidPRStatus, "only draft PRs can be marked ready")
+	}
+
+	author, err := s.getPRAuthor(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.autoAssignReviewers(ctx, s.candidatePoolInTx(tx), author, pr.ChangedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	events := assignedEvents(prID, models.OperationReady, reviewers)
+	if err := s.setPRReviewersAndStatusInTx(ctx, tx, prID, reviewers, models.PRStatusOpen, events); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "pull request marked ready", "reviewers", reviewerIDs(reviewers))
+
+	readyPR, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	readyPR.OwnerReviewers = ownerReviewerIDs(reviewers)
+
+	return readyPR, nil
+}
+
+func (s *Service) ClosePR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pr_id", prID)
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	pr, err := s.lockPR(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pr.Status {
+	case models.PRStatusClosed:
+		return pr, nil
+	case models.PRStatusMerged:
+		return nil, NewBusinessError(ErrorPRMerged, "cannot close merged PR")
+	}
+
+	if err := s.repo.UpdatePRStatusInTx(ctx, tx, prID, models.PRStatusClosed); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "pull request closed")
+
+	return s.repo.GetPR(ctx, prID)
+}
+
+// ReopenPR moves a closed PR back to OPEN. Reviewers who can no longer review it, because
+// they were deactivated, are absent or left the teams the author's team draws from, are
+// dropped and their slots are filled the same way as on creation.
+func (s *Service) ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pr_id", prID)
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	pr, err := s.lockPR(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pr.Status {
+	case models.PRStatusOpen:
+		return pr, nil
+	case models.PRStatusMerged:
+		return nil, NewBusinessError(ErrorPRMerged, "cannot reopen merged PR")
+	case models.PRStatusDraft:
+		return nil, NewBusinessError(ErrorInvalidPRStatus, "only closed PRs can be reopened")
+	}
+
+	author, err := s.getPRAuthor(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, events, err := s.revalidateReviewers(ctx, tx, pr, author)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setPRReviewersAndStatusInTx(ctx, tx, prID, reviewers, models.PRStatusOpen, events); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "pull request reopened", "reviewers", reviewerIDs(reviewers))
+
+	return s.repo.GetPR(ctx, prID)
+}
+
+// revalidateReviewers keeps the reviewers who are still candidates of the author's team
+// tiers, or of their own team as owners of the changed files, and fills the other slots.
+// Candidates are read in tx, so the check sees the same state the reassignment writes to.
+func (s *Service) revalidateReviewers(ctx context.Context, tx repository.Tx, pr *models.PullRequest, author *models.User) ([]selectedReviewer, []models.AssignmentEvent, error) {
+	pool := s.candidatePoolInTx(tx)
+	if len(pr.AssignedReviewers) == 0 {
+		reviewers, err := s.autoAssignReviewers(ctx, pool, author, pr.ChangedFiles)
+		if err != nil {
+			return nil, nil, err
+		}
+		return reviewers, assignedEvents(pr.PullRequestID, models.OperationReopen, reviewers), nil
+	}
+
+	team, err := s.authorTeam(ctx, author)
+	if err != nil {
+		return nil, nil, err
+	}
+	tiers := teamTiers(team)
+
+	eligible, err := s.eligibleReviewers(ctx, pool, tiers, pr.ChangedFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := []selectedReviewer{}
+	var events []models.AssignmentEvent
+	exclude := map[string]bool{author.UserID: true}
+	for _, reviewerID := range pr.AssignedReviewers {
+		exclude[reviewerID] = true
+		if eligible[reviewerID] {
+			kept = append(kept, selectedReviewer{UserID: reviewerID})
+			continue
+		}
+		events = append(events, removedEvent(pr.PullRequestID, models.OperationReopen, reviewerID, "reviewer no longer eligible on reopen"))
+	}
+
+	missing := len(pr.AssignedReviewers) - len(kept)
+	if missing == 0 {
+		return kept, nil, nil
+	}
+
+	selected, err := s.selectReviewers(ctx, team, tiers, pool, exclude, missing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reviewers := append(kept, selected...)
+	if len(reviewers) < team.MinReviewers {
+		return nil, nil, NewBusinessError(ErrorNotEnoughReviewers, fmt.Sprintf(
+			"team %s requires at least %d reviewers, only %d eligible", team.TeamName, team.MinReviewers, len(reviewers)))
+	}
+
+	events = append(events, assignedEvents(pr.PullRequestID, models.OperationReopen, selected)...)
+	return reviewers, events, nil
+}
+
+// eligibleReviewers returns the candidates of the tiers and the owners of the changed files
+// who are candidates of their own team. Review limits are not applied: a kept reviewer
+// already holds the review.
+func (s *Service) eligibleReviewers(ctx context.Context, pool *candidatePool, tiers []string, changedFiles []string) (map[string]bool, error) {
+	eligible := make(map[string]bool)
+	for _, tier := range tiers {
+		candidates, err := pool.candidates(ctx, tier)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			eligible[candidate.UserID] = true
+		}
+	}
+
+	owners, err := s.resolvePathOwners(ctx, changedFiles)
+	if err != nil {
+		return nil, err
+	}
+	for _, owner := range owners {
+		candidates, err := pool.candidates(ctx, owner.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if candidate.UserID == owner.UserID {
+				eligible[owner.UserID] = true
+			}
+		}
+	}
+	return eligible, nil
+}
+
+func (s *Service) setPRReviewersAndStatusInTx(ctx context.Context, tx repository.Tx, prID string, reviewers []selectedReviewer, status string, events []models.AssignmentEvent) error {
+	if err := s.repo.UpdatePRReviewersInTx(ctx, tx, prID, reviewerIDs(reviewers), reviewerFallbackTeams(reviewers)); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdatePRStatusInTx(ctx, tx, prID, status); err != nil {
+		return err
+	}
+
+	return s.repo.CreateAssignmentEventsInTx(ctx, tx, events)
+}
+
+// lockPR locks the PR until tx ends and reads it under the lock, so the caller decides
+// on a state that no concurrent transition can change before the transaction commits.
+func (s *Service) lockPR(ctx context.Context, tx repository.Tx, prID string) (*models.PullRequest, error) {
+	status, err := s.repo.LockPRInTx(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+	return s.repo.GetPRInTx(ctx, tx, prID)
+}
+
+func (s *Service) getPRAuthor(ctx context.Context, pr *models.PullRequest) (*models.User, error) {
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, NewBusinessError(ErrorNotFound, "author not found")
+	}
+	return author, nil
+}
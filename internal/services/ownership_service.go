@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Service) ImportOwnership(ctx context.Context, content string) ([]models.OwnershipRule, error) {
+	lines, err := parseCodeowners(content)
+	if err != nil {
+		return nil, NewBusinessError(ErrorInvalidOwnership, err.Error())
+	}
+
+	var rules []models.OwnershipRule
+	for position, line := range lines {
+		if len(line.Owners) == 0 {
+			rules = append(rules, models.OwnershipRule{Position: position, Pattern: line.Pattern})
+			continue
+		}
+
+		for _, owner := range line.Owners {
+			rule, err := s.resolveOwnershipOwner(ctx, owner)
+			if err != nil {
+				return nil, err
+			}
+			if rule == nil {
+				return nil, NewBusinessError(ErrorInvalidOwnership, fmt.Sprintf("line %d: unknown owner %s", line.Number, owner))
+			}
+			rule.Position = position
+			rule.Pattern = line.Pattern
+			rules = append(rules, *rule)
+		}
+	}
+
+	if err := s.repo.ReplaceOwnershipRules(ctx, rules); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetOwnershipRules(ctx)
+}
+
+func (s *Service) GetOwnershipRules(ctx context.Context) ([]models.OwnershipRule, error) {
+	return s.repo.GetOwnershipRules(ctx)
+}
+
+func (s *Service) resolveOwnershipOwner(ctx context.Context, owner string) (*models.OwnershipRule, error) {
+	if !strings.HasPrefix(owner, "@") {
+		return nil, nil
+	}
+	name := strings.TrimPrefix(owner, "@")
+
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		return s.ownershipTeam(ctx, name[idx+1:])
+	}
+
+	user, err := s.repo.GetUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		return &models.OwnershipRule{OwnerUserID: user.UserID}, nil
+	}
+
+	return s.ownershipTeam(ctx, name)
+}
+
+func (s *Service) ownershipTeam(ctx context.Context, teamName string) (*models.OwnershipRule, error) {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	return &models.OwnershipRule{OwnerTeamName: teamName}, nil
+}
+
+// resolvePathOwners returns active owners of the given paths. As in CODEOWNERS,
+// the last matching rule wins for every path.
+func (s *Service) resolvePathOwners(ctx context.Context, paths []string) ([]models.User, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	rules, err := s.repo.GetOwnershipRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ownerUserIDs, ownerTeams []string
+	for _, path := range paths {
+		matched := -1
+		for _, rule := range rules {
+			if rule.Position > matched && matchOwnershipPattern(rule.Pattern, path) {
+				matched = rule.Position
+			}
+		}
+
+		for _, rule := range rules {
+			if rule.Position != matched {
+				continue
+			}
+			if rule.OwnerUserID != "" && !contains(ownerUserIDs, rule.OwnerUserID) {
+				ownerUserIDs = append(ownerUserIDs, rule.OwnerUserID)
+			}
+			if rule.OwnerTeamName != "" && !contains(ownerTeams, rule.OwnerTeamName) {
+				ownerTeams = append(ownerTeams, rule.OwnerTeamName)
+			}
+		}
+	}
+
+	var owners []models.User
+	seen := make(map[string]bool)
+	for _, userID := range ownerUserIDs {
+		user, err := s.repo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && user.IsActive && !seen[user.UserID] {
+			seen[user.UserID] = true
+			owners = append(owners, *user)
+		}
+	}
+	for _, teamName := range ownerTeams {
+		users, err := s.repo.GetActiveUsersByTeam(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if !seen[user.UserID] {
+				seen[user.UserID] = true
+				owners = append(owners, user)
+			}
+		}
+	}
+
+	return owners, nil
+}
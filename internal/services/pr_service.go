@@ -1,11 +1,18 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/lypolix/avito_test/internal/logging"
 	"github.com/lypolix/avito_test/internal/models"
 )
 
-func (s *Service) CreatePR(prRequest *models.CreatePRRequest) (*models.PullRequest, error) {
-	exists, err := s.repo.PRExists(prRequest.PullRequestID)
+func (s *Service) CreatePR(ctx context.Context, prRequest *models.CreatePRRequest) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pr_id", prRequest.PullRequestID, "user_id", prRequest.AuthorID)
+
+	exists, err := s.repo.PRExists(ctx, prRequest.PullRequestID)
 	if err != nil {
 		return nil, err
 	}
@@ -13,7 +20,7 @@ func (s *Service) CreatePR(prRequest *models.CreatePRRequest) (*models.PullReque
 		return nil, NewBusinessError(ErrorPRExists, "PR id already exists")
 	}
 
-	author, err := s.repo.GetUser(prRequest.AuthorID)
+	author, err := s.repo.GetUser(ctx, prRequest.AuthorID)
 	if err != nil {
 		return nil, err
 	}
@@ -21,85 +28,276 @@ func (s *Service) CreatePR(prRequest *models.CreatePRRequest) (*models.PullReque
 		return nil, NewBusinessError(ErrorNotFound, "resource not found")
 	}
 
-	reviewers, err := s.autoAssignReviewers(author)
-	if err != nil {
-		return nil, err
-	}
-
 	pr := &models.PullRequest{
 		PullRequestID:     prRequest.PullRequestID,
 		PullRequestName:   prRequest.PullRequestName,
 		AuthorID:          prRequest.AuthorID,
-		Status:            "OPEN",
-		AssignedReviewers: reviewers,
+		Status:            models.PRStatusDraft,
+		AssignedReviewers: []string{},
+		ChangedFiles:      prRequest.ChangedFiles,
 	}
 
-	if err := s.repo.CreatePR(pr); err != nil {
+	var events []models.AssignmentEvent
+	if !prRequest.Draft {
+		reviewers, err := s.autoAssignReviewers(ctx, newCandidatePool(s.repo.GetReviewCandidates), author, prRequest.ChangedFiles)
+		if err != nil {
+			return nil, err
+		}
+
+		pr.Status = models.PRStatusOpen
+		pr.AssignedReviewers = reviewerIDs(reviewers)
+		pr.FallbackReviewers = fallbackReviewerIDs(reviewers)
+		pr.ReviewerFallbackTeams = reviewerFallbackTeams(reviewers)
+		pr.OwnerReviewers = ownerReviewerIDs(reviewers)
+		events = assignedEvents(pr.PullRequestID, models.OperationCreate, reviewers)
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
+	if err := s.repo.CreatePRInTx(ctx, tx, pr); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateAssignmentEventsInTx(ctx, tx, events); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "pull request created", "status", pr.Status, "reviewers", pr.AssignedReviewers)
 	return pr, nil
 }
 
-func (s *Service) MergePR(prID string) (*models.PullRequest, error) {
-	pr, err := s.repo.GetPR(prID)
+func (s *Service) MergePR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.mergePR(ctx, prID, nil)
+}
+
+// ForceMergePR merges the PR even if the merge policy is not satisfied and records the
+// override. The caller fills Actor with the authenticated identity behind the merge and
+// may fill RequestedBy and Reason; the PR and the unmet rules are filled here.
+func (s *Service) ForceMergePR(ctx context.Context, prID string, override *models.MergeOverride) (*models.PullRequest, error) {
+	return s.mergePR(ctx, prID, override)
+}
+
+// mergePR checks the merge policy and merges in one transaction that holds the PR row
+// lock, so the policy cannot change its verdict between the check and the merge.
+// A nil override means a regular merge.
+func (s *Service) mergePR(ctx context.Context, prID string, override *models.MergeOverride) (*models.PullRequest, error) {
+	ctx = logging.With(ctx, "pr_id", prID)
+
+	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if pr == nil {
-		return nil, NewBusinessError(ErrorNotFound, "resource not found")
-	}
+	defer tx.Rollback()
 
-	if pr.Status == "MERGED" {
+	pr, err := s.lockPR(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
 		return pr, nil
+	case models.PRStatusOpen:
+	default:
+		return nil, NewBusinessError(ErrorInvalidPRStatus, "only open PRs can be merged")
+	}
+
+	unmetRules, err := s.unmetMergeRules(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if override == nil {
+		if len(unmetRules) > 0 {
+			return nil, NewBusinessErrorWithDetails(ErrorMergeBlocked, "merge policy is not satisfied", unmetRules)
+		}
+	} else {
+		override.PullRequestID = prID
+		override.UnmetRules = unmetRules
+		if err := s.repo.CreateMergeOverrideInTx(ctx, tx, override); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.repo.UpdatePRStatus(prID, "MERGED"); err != nil {
+	if err := s.repo.UpdatePRStatusInTx(ctx, tx, prID, models.PRStatusMerged); err != nil {
 		return nil, err
 	}
 
-	mergedPR, err := s.repo.GetPR(prID)
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if override == nil {
+		slog.InfoContext(ctx, "pull request merged")
+	} else {
+		slog.WarnContext(ctx, "pull request force-merged", "actor", override.Actor,
+			"requested_by", override.RequestedBy, "reason", override.Reason, "unmet_rules", unmetRules)
+	}
+
+	return s.repo.GetPR(ctx, prID)
+}
+
+func (s *Service) unmetMergeRules(ctx context.Context, pr *models.PullRequest) ([]string, error) {
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
 	if err != nil {
 		return nil, err
 	}
+	if author == nil {
+		return nil, NewBusinessError(ErrorNotFound, "author not found")
+	}
 
-	return mergedPR, nil
+	team, err := s.authorTeam(ctx, author)
+	if err != nil {
+		return nil, err
+	}
+
+	unmetRules := []string{}
+
+	approvals := 0
+	for _, state := range pr.ReviewerStates {
+		switch state.State {
+		case models.ReviewApproved:
+			approvals++
+		case models.ReviewChangesRequested:
+			if team.BlockOnChangesRequested {
+				unmetRules = append(unmetRules, "changes requested by "+state.UserID)
+			}
+		}
+	}
+	if approvals < team.RequiredApprovals {
+		unmetRules = append(unmetRules, fmt.Sprintf("requires %d approvals, has %d", team.RequiredApprovals, approvals))
+	}
+
+	if team.RequireOwnerApproval {
+		approved, required, err := s.hasOwnerApproval(ctx, pr)
+		if err != nil {
+			return nil, err
+		}
+		if required && !approved {
+			unmetRules = append(unmetRules, "requires approval from a code owner")
+		}
+	}
+
+	return unmetRules, nil
 }
 
-func (s *Service) ReassignReviewer(prID, oldUserID string) (*models.ReassignResponse, error) {
-	pr, err := s.repo.GetPR(prID)
+func (s *Service) hasOwnerApproval(ctx context.Context, pr *models.PullRequest) (approved bool, required bool, err error) {
+	owners, err := s.resolvePathOwners(ctx, pr.ChangedFiles)
+	if err != nil {
+		return false, false, err
+	}
+	if len(owners) == 0 {
+		return false, false, nil
+	}
+
+	// Only approvals of current reviewers given since their assignment count, as for
+	// required_approvals: an owner who approved and was then replaced no longer approves.
+	for _, owner := range owners {
+		if owner.UserID != pr.AuthorID && reviewerState(pr, owner.UserID) == models.ReviewApproved {
+			return true, true, nil
+		}
+	}
+	return false, true, nil
+}
+
+func (s *Service) GetMergeOverrides(ctx context.Context, prID string) (*models.MergeOverridesResponse, error) {
+	exists, err := s.repo.PRExists(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
-	if pr == nil {
+	if !exists {
 		return nil, NewBusinessError(ErrorNotFound, "resource not found")
 	}
 
-	if pr.Status == "MERGED" {
+	overrides, err := s.repo.GetMergeOverrides(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MergeOverridesResponse{
+		PullRequestID: prID,
+		Overrides:     overrides,
+	}, nil
+}
+
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string, force bool) (*models.ReassignResponse, error) {
+	return s.reassignReviewer(ctx, prID, oldUserID, force, models.OperationReassign, "reassigned on request")
+}
+
+// reassignReviewer replaces oldUserID on the PR and records the change under operation with reason.
+// The PR stays locked from the read to the write, so concurrent reassigns, merges and
+// deactivations act on each other's results instead of overwriting them.
+func (s *Service) reassignReviewer(ctx context.Context, prID, oldUserID string, force bool, operation, reason string) (*models.ReassignResponse, error) {
+	ctx = logging.With(ctx, "pr_id", prID)
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	pr, err := s.lockPR(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == models.PRStatusMerged {
 		return nil, NewBusinessError(ErrorPRMerged, "cannot reassign on merged PR")
 	}
+	if pr.Status != models.PRStatusOpen {
+		return nil, NewBusinessError(ErrorInvalidPRStatus, "only open PRs can be reassigned")
+	}
 
 	if !contains(pr.AssignedReviewers, oldUserID) {
 		return nil, NewBusinessError(ErrorNotAssigned, "reviewer is not assigned to this PR")
 	}
 
-	newReviewerID, err := s.findReplacementReviewer(oldUserID, pr.AssignedReviewers, pr.AuthorID)
+	if !force && reviewerState(pr, oldUserID) == models.ReviewApproved {
+		return nil, NewBusinessError(ErrorReviewerApproved, "reviewer has already approved this PR, pass force to replace")
+	}
+
+	newReviewer, err := s.findReplacementReviewer(ctx, tx, oldUserID, pr.AssignedReviewers, pr.AuthorID)
 	if err != nil {
 		return nil, err
 	}
 
-	newReviewers := replaceInSlice(pr.AssignedReviewers, oldUserID, newReviewerID)
-	if err := s.repo.UpdatePRReviewers(prID, newReviewers); err != nil {
+	if reviewerState(pr, oldUserID) == models.ReviewApproved {
+		reason = "approved reviewer reassigned with force"
+	}
+
+	newReviewers := replaceInSlice(pr.AssignedReviewers, oldUserID, newReviewer.UserID)
+	event := replacedEvent(prID, operation, oldUserID, newReviewer, reason)
+	fallbackTeams := reviewerFallbackTeams([]selectedReviewer{newReviewer})
+	if err := s.repo.UpdatePRReviewersInTx(ctx, tx, prID, newReviewers, fallbackTeams); err != nil {
 		return nil, err
 	}
 
-	updatedPR, err := s.repo.GetPR(prID)
+	if err := s.repo.CreateAssignmentEventsInTx(ctx, tx, []models.AssignmentEvent{event}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "reviewer reassigned", "operation", operation, "old_reviewer", oldUserID,
+		"new_reviewer", newReviewer.UserID, "fallback_team", newReviewer.FallbackTeam)
+
+	updatedPR, err := s.repo.GetPR(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.ReassignResponse{
-		PR:         updatedPR,
-		ReplacedBy: newReviewerID,
+		PR:           updatedPR,
+		ReplacedBy:   newReviewer.UserID,
+		FallbackTeam: newReviewer.FallbackTeam,
 	}, nil
 }
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Service) SubmitReview(ctx context.Context, req *models.SubmitReviewRequest) (*models.ReviewResponse, error) {
+	if !isReviewState(req.State) {
+		return nil, NewBusinessError(ErrorInvalidReviewState, "state must be one of APPROVED, CHANGES_REQUESTED, COMMENTED")
+	}
+
+	// The PR stays locked until the review is stored, so a merge, close or reassignment
+	// running at the same time cannot take the reviewer off between the checks and the insert.
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status, err := s.repo.LockPRInTx(ctx, tx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case "":
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	case models.PRStatusMerged:
+		return nil, NewBusinessError(ErrorPRMerged, "cannot review merged PR")
+	case models.PRStatusOpen:
+	default:
+		return nil, NewBusinessError(ErrorInvalidPRStatus, "only open PRs can be reviewed")
+	}
+
+	reviewers, err := s.repo.GetPRReviewersInTx(ctx, tx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(reviewers, req.UserID) {
+		return nil, NewBusinessError(ErrorNotAssigned, "reviewer is not assigned to this PR")
+	}
+
+	review := &models.Review{
+		PullRequestID: req.PullRequestID,
+		UserID:        req.UserID,
+		State:         req.State,
+		Comment:       req.Comment,
+	}
+	if err := s.repo.CreateReviewInTx(ctx, tx, review); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	updatedPR, err := s.repo.GetPR(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReviewResponse{
+		Review: review,
+		PR:     updatedPR,
+	}, nil
+}
+
+func (s *Service) GetPRReviews(ctx context.Context, prID string) (*models.ReviewsResponse, error) {
+	exists, err := s.repo.PRExists(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	reviews, err := s.repo.GetPRReviews(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReviewsResponse{
+		PullRequestID: prID,
+		Reviews:       reviews,
+	}, nil
+}
+
+func isReviewState(state string) bool {
+	switch state {
+	case models.ReviewApproved, models.ReviewChangesRequested, models.ReviewCommented:
+		return true
+	default:
+		return false
+	}
+}
+
+func reviewerState(pr *models.PullRequest, userID string) string {
+	for _, state := range pr.ReviewerStates {
+		if state.UserID == userID {
+			return state.State
+		}
+	}
+	return models.ReviewPending
+}
@@ -1,61 +1,297 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
 
 	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
 )
 
-func (s *Service) autoAssignReviewers(author *models.User) ([]string, error) {
-	teamUsers, err := s.repo.GetActiveUsersByTeam(author.TeamName)
+type selectedReviewer struct {
+	UserID       string
+	FallbackTeam string
+	Owner        bool
+}
+
+type candidatePool struct {
+	load  func(ctx context.Context, teamName string) ([]models.ReviewCandidate, error)
+	cache map[string][]models.ReviewCandidate
+}
+
+func newCandidatePool(load func(ctx context.Context, teamName string) ([]models.ReviewCandidate, error)) *candidatePool {
+	return &candidatePool{load: load, cache: make(map[string][]models.ReviewCandidate)}
+}
+
+// candidatePoolInTx reads candidates in tx, so they reflect the changes and locks of the
+// transaction that assigns them.
+func (s *Service) candidatePoolInTx(tx repository.Tx) *candidatePool {
+	return newCandidatePool(func(ctx context.Context, teamName string) ([]models.ReviewCandidate, error) {
+		return s.repo.GetReviewCandidatesInTx(ctx, tx, teamName)
+	})
+}
+
+func (p *candidatePool) candidates(ctx context.Context, teamName string) ([]models.ReviewCandidate, error) {
+	if candidates, ok := p.cache[teamName]; ok {
+		return candidates, nil
+	}
+	candidates, err := p.load(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
+	p.cache[teamName] = candidates
+	return candidates, nil
+}
 
-	var candidates []string
-	for _, user := range teamUsers {
-		if user.UserID != author.UserID {
-			candidates = append(candidates, user.UserID)
+func (p *candidatePool) addOpenReview(userID string) {
+	for _, candidates := range p.cache {
+		for i := range candidates {
+			if candidates[i].UserID == userID {
+				candidates[i].OpenReviews++
+			}
 		}
 	}
-	shuffle(candidates)
+}
+
+func (s *Service) autoAssignReviewers(ctx context.Context, pool *candidatePool, author *models.User, changedFiles []string) ([]selectedReviewer, error) {
+	team, err := s.repo.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
+	}
+
+	exclude := map[string]bool{author.UserID: true}
+	tiers := teamTiers(team)
+
+	owners, err := s.resolvePathOwners(ctx, changedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := s.selectOwnerReviewers(ctx, team, owners, pool, exclude, team.MaxReviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := s.selectReviewers(ctx, team, tiers, pool, exclude, team.MaxReviewers-len(selected))
+	if err != nil {
+		return nil, err
+	}
+	selected = append(selected, rest...)
 
-	maxReviewers := 2
-	if len(candidates) < maxReviewers {
-		maxReviewers = len(candidates)
+	if len(selected) < team.MinReviewers {
+		return nil, NewBusinessError(ErrorNotEnoughReviewers, fmt.Sprintf(
+			"team %s requires at least %d reviewers, only %d eligible", team.TeamName, team.MinReviewers, len(selected)))
 	}
 
-	return candidates[:maxReviewers], nil
+	return selected, nil
 }
 
-func (s *Service) findReplacementReviewer(oldUserID string, currentReviewers []string, authorID string) (string, error) {
-	oldReviewer, err := s.repo.GetUser(oldUserID)
+func (s *Service) findReplacementReviewer(ctx context.Context, tx repository.Tx, oldUserID string, currentReviewers []string, authorID string) (selectedReviewer, error) {
+	oldReviewer, err := s.repo.GetUser(ctx, oldUserID)
 	if err != nil {
-		return "", err
+		return selectedReviewer{}, err
 	}
 	if oldReviewer == nil {
-		return "", NewBusinessError(ErrorNotFound, "old reviewer not found")
+		return selectedReviewer{}, NewBusinessError(ErrorNotFound, "old reviewer not found")
+	}
+
+	author, err := s.repo.GetUser(ctx, authorID)
+	if err != nil {
+		return selectedReviewer{}, err
+	}
+	if author == nil {
+		return selectedReviewer{}, NewBusinessError(ErrorNotFound, "author not found")
+	}
+
+	team, err := s.authorTeam(ctx, author)
+	if err != nil {
+		return selectedReviewer{}, err
+	}
+
+	exclude := map[string]bool{oldUserID: true, authorID: true}
+	for _, reviewer := range currentReviewers {
+		exclude[reviewer] = true
+	}
+
+	tiers := reviewerTiers(oldReviewer.TeamName, teamTiers(team))
+	selected, err := s.selectReviewers(ctx, team, tiers, s.candidatePoolInTx(tx), exclude, 1)
+	if err != nil {
+		return selectedReviewer{}, err
+	}
+	if len(selected) == 0 {
+		return selectedReviewer{}, NewBusinessError(ErrorNoCandidate, "no active replacement candidate in team")
+	}
+
+	return selected[0], nil
+}
+
+// selectReviewers fills up to count slots tier by tier: the team itself first,
+// then its fallback teams in order. Every tier after the team is reported as a fallback.
+func (s *Service) selectReviewers(ctx context.Context, team *models.Team, tiers []string, pool *candidatePool, exclude map[string]bool, count int) ([]selectedReviewer, error) {
+	strategy := s.strategyOf(team)
+
+	var selected []selectedReviewer
+	for _, tier := range tiers {
+		if len(selected) >= count {
+			break
+		}
+
+		tierCandidates, err := pool.candidates(ctx, tier)
+		if err != nil {
+			return nil, err
+		}
+
+		var candidates []models.ReviewCandidate
+		for _, candidate := range tierCandidates {
+			if !exclude[candidate.UserID] && !candidate.AtReviewLimit() {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		for _, userID := range strategy.Select(tier, candidates, count-len(selected)) {
+			reviewer := selectedReviewer{UserID: userID}
+			if tier != team.TeamName {
+				reviewer.FallbackTeam = tier
+			}
+			exclude[userID] = true
+			pool.addOpenReview(userID)
+			selected = append(selected, reviewer)
+		}
+	}
+
+	return selected, nil
+}
+
+func (s *Service) selectOwnerReviewers(ctx context.Context, team *models.Team, owners []models.User, pool *candidatePool, exclude map[string]bool, count int) ([]selectedReviewer, error) {
+	ownerSet := make(map[string]bool)
+	var ownerTeams []string
+	for _, owner := range owners {
+		ownerSet[owner.UserID] = true
+		if !contains(ownerTeams, owner.TeamName) {
+			ownerTeams = append(ownerTeams, owner.TeamName)
+		}
 	}
 
-	teamUsers, err := s.repo.GetActiveUsersByTeam(oldReviewer.TeamName)
+	var candidates []models.ReviewCandidate
+	for _, teamName := range ownerTeams {
+		teamCandidates, err := pool.candidates(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range teamCandidates {
+			if ownerSet[candidate.UserID] && !exclude[candidate.UserID] && !candidate.AtReviewLimit() {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	var selected []selectedReviewer
+	for _, userID := range s.strategyOf(team).Select(team.TeamName, candidates, count) {
+		exclude[userID] = true
+		pool.addOpenReview(userID)
+		selected = append(selected, selectedReviewer{UserID: userID, Owner: true})
+	}
+
+	return selected, nil
+}
+
+// authorTeam returns the settings of the author's team. An author removed from their
+// team keeps their open PRs; those get the default policy and, having no team of their
+// own, draw replacement reviewers from the team of the reviewer being replaced.
+func (s *Service) authorTeam(ctx context.Context, author *models.User) (*models.Team, error) {
+	if author.TeamName == "" {
+		return &models.Team{
+			ReviewerStrategy: DefaultStrategy,
+			MaxReviewers:     DefaultMaxReviewers,
+			FallbackTeams:    []string{},
+		}, nil
+	}
+
+	team, err := s.repo.GetTeamSettings(ctx, author.TeamName)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if team == nil {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
 	}
+	return team, nil
+}
+
+// teamTiers orders the teams searched for reviewers: the team itself, its fallback
+// teams, and then its ancestors, nearest first.
+func teamTiers(team *models.Team) []string {
+	return reviewerTiers(team.TeamName, append(append([]string{}, team.FallbackTeams...), team.Ancestors...))
+}
+
+func reviewerTiers(primary string, others []string) []string {
+	var tiers []string
+	for _, teamName := range append([]string{primary}, others...) {
+		if teamName != "" && !contains(tiers, teamName) {
+			tiers = append(tiers, teamName)
+		}
+	}
+	return tiers
+}
+
+func reviewerIDs(reviewers []selectedReviewer) []string {
+	ids := make([]string, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		ids = append(ids, reviewer.UserID)
+	}
+	return ids
+}
+
+func fallbackReviewerIDs(reviewers []selectedReviewer) []string {
+	var ids []string
+	for _, reviewer := range reviewers {
+		if reviewer.FallbackTeam != "" {
+			ids = append(ids, reviewer.UserID)
+		}
+	}
+	return ids
+}
+
+// reviewerFallbackTeams maps reviewers drawn from another team to that team, which is
+// stored with their assignment.
+func reviewerFallbackTeams(reviewers []selectedReviewer) map[string]string {
+	teams := make(map[string]string)
+	for _, reviewer := range reviewers {
+		if reviewer.FallbackTeam != "" {
+			teams[reviewer.UserID] = reviewer.FallbackTeam
+		}
+	}
+	return teams
+}
 
-	var candidates []string
-	for _, user := range teamUsers {
-		if user.UserID != oldUserID &&
-			user.UserID != authorID &&
-			!contains(currentReviewers, user.UserID) {
-			candidates = append(candidates, user.UserID)
+func ownerReviewerIDs(reviewers []selectedReviewer) []string {
+	var ids []string
+	for _, reviewer := range reviewers {
+		if reviewer.Owner {
+			ids = append(ids, reviewer.UserID)
 		}
 	}
+	return ids
+}
 
-	if len(candidates) == 0 {
-		return "", NewBusinessError(ErrorNoCandidate, "no active replacement candidate in team")
+func (s *Service) strategyFor(ctx context.Context, teamName string) (ReviewerStrategy, error) {
+	team, err := s.repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
 	}
+	return s.strategyOf(team), nil
+}
 
-	return candidates[rand.Intn(len(candidates))], nil
+func (s *Service) strategyOf(team *models.Team) ReviewerStrategy {
+	if team != nil {
+		if strategy, ok := s.strategies[team.ReviewerStrategy]; ok {
+			return strategy
+		}
+	}
+	return s.strategies[DefaultStrategy]
 }
 
 func contains(slice []string, item string) bool {
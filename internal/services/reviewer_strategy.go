@@ -0,0 +1,146 @@
+package services
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+
+	DefaultStrategy = StrategyLeastLoaded
+
+	DefaultMaxReviewers = 2
+	MaxReviewersLimit   = 10
+)
+
+// ReviewerStrategy picks up to count reviewers out of already filtered candidates.
+type ReviewerStrategy interface {
+	Select(teamName string, candidates []models.ReviewCandidate, count int) []string
+}
+
+func defaultStrategies() map[string]ReviewerStrategy {
+	return map[string]ReviewerStrategy{
+		StrategyRandom:      &RandomStrategy{},
+		StrategyRoundRobin:  &RoundRobinStrategy{},
+		StrategyLeastLoaded: &LeastLoadedStrategy{},
+		StrategyWeighted:    &WeightedStrategy{},
+	}
+}
+
+type RandomStrategy struct{}
+
+func (st *RandomStrategy) Select(_ string, candidates []models.ReviewCandidate, count int) []string {
+	ids := candidateIDs(candidates)
+	shuffle(ids)
+	return ids[:limit(count, len(ids))]
+}
+
+// RoundRobinStrategy walks team members in user_id order, continuing after the
+// candidate that was assigned most recently. The cursor comes from the stored
+// assignment events, which are written in the assignment transaction, so it survives
+// restarts and is shared by every instance.
+type RoundRobinStrategy struct{}
+
+func (st *RoundRobinStrategy) Select(_ string, candidates []models.ReviewCandidate, count int) []string {
+	ordered := make([]models.ReviewCandidate, len(candidates))
+	copy(ordered, candidates)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].UserID < ordered[j].UserID })
+
+	n := limit(count, len(ordered))
+	if n == 0 {
+		return []string{}
+	}
+
+	start := 0
+	var last int64
+	for i, candidate := range ordered {
+		if candidate.LastAssignmentID > last {
+			last = candidate.LastAssignmentID
+			start = i + 1
+		}
+	}
+
+	selected := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		selected = append(selected, ordered[(start+i)%len(ordered)].UserID)
+	}
+	return selected
+}
+
+// LeastLoadedStrategy prefers candidates with the fewest open reviews, ties are broken randomly.
+type LeastLoadedStrategy struct{}
+
+func (st *LeastLoadedStrategy) Select(_ string, candidates []models.ReviewCandidate, count int) []string {
+	ordered := make([]models.ReviewCandidate, len(candidates))
+	copy(ordered, candidates)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].OpenReviews < ordered[j].OpenReviews
+	})
+
+	ids := candidateIDs(ordered)
+	return ids[:limit(count, len(ids))]
+}
+
+// WeightedStrategy draws candidates without replacement with probability
+// proportional to their review weight.
+type WeightedStrategy struct{}
+
+func (st *WeightedStrategy) Select(_ string, candidates []models.ReviewCandidate, count int) []string {
+	pool := make([]models.ReviewCandidate, len(candidates))
+	copy(pool, candidates)
+
+	n := limit(count, len(pool))
+	selected := make([]string, 0, n)
+	for len(selected) < n {
+		total := 0
+		for _, candidate := range pool {
+			total += candidateWeight(candidate)
+		}
+
+		pick := rand.Intn(total)
+		for i, candidate := range pool {
+			pick -= candidateWeight(candidate)
+			if pick < 0 {
+				selected = append(selected, candidate.UserID)
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+func candidateWeight(candidate models.ReviewCandidate) int {
+	if candidate.Weight < 1 {
+		return 1
+	}
+	return candidate.Weight
+}
+
+func candidateIDs(candidates []models.ReviewCandidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.UserID)
+	}
+	return ids
+}
+
+func limit(count, available int) int {
+	if count > available {
+		return available
+	}
+	if count < 0 {
+		return 0
+	}
+	return count
+}
@@ -5,27 +5,54 @@ import (
 )
 
 const (
-	ErrorTeamExists      = "TEAM_EXISTS"
-	ErrorPRExists        = "PR_EXISTS"
-	ErrorPRMerged        = "PR_MERGED"
-	ErrorNotAssigned     = "NOT_ASSIGNED"
-	ErrorNoCandidate     = "NO_CANDIDATE"
-	ErrorNotFound        = "NOT_FOUND"
-	ErrorInvalidTeam     = "INVALID_TEAM"
-	ErrorUserInOtherTeam = "USER_IN_OTHER_TEAM"
+	ErrorTeamExists         = "TEAM_EXISTS"
+	ErrorPRExists           = "PR_EXISTS"
+	ErrorPRMerged           = "PR_MERGED"
+	ErrorNotAssigned        = "NOT_ASSIGNED"
+	ErrorNoCandidate        = "NO_CANDIDATE"
+	ErrorNotFound           = "NOT_FOUND"
+	ErrorInvalidTeam        = "INVALID_TEAM"
+	ErrorUserInOtherTeam    = "USER_IN_OTHER_TEAM"
+	ErrorInvalidStrategy    = "INVALID_STRATEGY"
+	ErrorInvalidLimit       = "INVALID_LIMIT"
+	ErrorNotEnoughReviewers = "NOT_ENOUGH_REVIEWERS"
+	ErrorInvalidOwnership   = "INVALID_OWNERSHIP"
+	ErrorInvalidReviewState = "INVALID_REVIEW_STATE"
+	ErrorReviewerApproved   = "REVIEWER_APPROVED"
+	ErrorMergeBlocked       = "MERGE_BLOCKED"
+	ErrorInvalidPRStatus    = "INVALID_PR_STATUS"
+	ErrorInvalidWebhook     = "INVALID_WEBHOOK"
+	ErrorInvalidAccount     = "INVALID_ACCOUNT"
+	ErrorUnknownAccount     = "UNKNOWN_ACCOUNT"
+	ErrorInvalidPayload     = "INVALID_PAYLOAD"
+	ErrorInvalidAbsence     = "INVALID_ABSENCE"
+	ErrorTeamNotEmpty       = "TEAM_NOT_EMPTY"
+
+	ErrorInvalidIdempotencyKey = "INVALID_IDEMPOTENCY_KEY"
+	ErrorIdempotencyKeyReused  = "IDEMPOTENCY_KEY_REUSED"
+	ErrorRequestInProgress     = "REQUEST_IN_PROGRESS"
 )
 
 type Service struct {
-	repo *repository.Repository
+	repo       repository.Store
+	strategies map[string]ReviewerStrategy
+}
+
+func NewService(repo repository.Store) *Service {
+	return &Service{
+		repo:       repo,
+		strategies: defaultStrategies(),
+	}
 }
 
-func NewService(repo *repository.Repository) *Service {
-	return &Service{repo: repo}
+func (s *Service) RegisterStrategy(name string, strategy ReviewerStrategy) {
+	s.strategies[name] = strategy
 }
 
 type BusinessError struct {
 	Code    string
 	Message string
+	Details []string
 }
 
 func (e *BusinessError) Error() string {
@@ -35,3 +62,7 @@ func (e *BusinessError) Error() string {
 func NewBusinessError(code, message string) error {
 	return &BusinessError{Code: code, Message: message}
 }
+
+func NewBusinessErrorWithDetails(code, message string, details []string) error {
+	return &BusinessError{Code: code, Message: message, Details: details}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// ServiceTestSuite runs the service against the in-memory store, so it needs no database.
+type ServiceTestSuite struct {
+	suite.Suite
+	repo    *memory.Store
+	service *Service
+	ctx     context.Context
+}
+
+func TestServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceTestSuite))
+}
+
+func (ts *ServiceTestSuite) SetupTest() {
+	ts.repo = memory.New()
+	ts.service = NewService(ts.repo)
+	ts.ctx = context.Background()
+}
+
+func (ts *ServiceTestSuite) createTeam(team *models.Team, userIDs ...string) {
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, models.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	require.NoError(ts.T(), ts.service.CreateTeam(ts.ctx, team))
+}
+
+func (ts *ServiceTestSuite) createPR(prID, authorID string) *models.PullRequest {
+	pr, err := ts.service.CreatePR(ts.ctx, &models.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: prID,
+		AuthorID:        authorID,
+	})
+	require.NoError(ts.T(), err)
+	return pr
+}
+
+func (ts *ServiceTestSuite) assertBusinessError(err error, code string) {
+	var businessErr *BusinessError
+	if assert.True(ts.T(), errors.As(err, &businessErr), "expected a business error, got %v", err) {
+		assert.Equal(ts.T(), code, businessErr.Code)
+	}
+}
+
+func (ts *ServiceTestSuite) TestCreatePR_LeastLoadedPicksIdleReviewers() {
+	ts.createTeam(&models.Team{TeamName: "backend", MaxReviewers: 1}, "author", "reviewer1", "reviewer2")
+
+	first := ts.createPR("pr-1", "author")
+	require.Len(ts.T(), first.AssignedReviewers, 1)
+
+	second := ts.createPR("pr-2", "author")
+	require.Len(ts.T(), second.AssignedReviewers, 1)
+	assert.NotEqual(ts.T(), first.AssignedReviewers[0], second.AssignedReviewers[0])
+}
+
+func (ts *ServiceTestSuite) TestCreatePR_UsesTeamStrategy() {
+	ts.service.RegisterStrategy("first", firstCandidateStrategy{})
+	ts.createTeam(&models.Team{TeamName: "backend", ReviewerStrategy: "first", MaxReviewers: 1}, "author", "zed", "amy")
+
+	pr := ts.createPR("pr-1", "author")
+	assert.Equal(ts.T(), []string{"amy"}, pr.AssignedReviewers)
+}
+
+func (ts *ServiceTestSuite) TestCreateTeam_RejectsUnknownStrategy() {
+	err := ts.service.CreateTeam(ts.ctx, &models.Team{TeamName: "backend", ReviewerStrategy: "nope"})
+	ts.assertBusinessError(err, ErrorInvalidStrategy)
+}
+
+func (ts *ServiceTestSuite) TestMergePR_WaitsForRequiredApprovals() {
+	ts.createTeam(&models.Team{TeamName: "backend", RequiredApprovals: 1}, "author", "reviewer1", "reviewer2")
+	pr := ts.createPR("pr-1", "author")
+
+	_, err := ts.service.MergePR(ts.ctx, pr.PullRequestID)
+	ts.assertBusinessError(err, ErrorMergeBlocked)
+
+	_, err = ts.service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: pr.PullRequestID,
+		UserID:        pr.AssignedReviewers[0],
+		State:         models.ReviewApproved,
+	})
+	require.NoError(ts.T(), err)
+
+	merged, err := ts.service.MergePR(ts.ctx, pr.PullRequestID)
+	require.NoError(ts.T(), err)
+	assert.Equal(ts.T(), models.PRStatusMerged, merged.Status)
+	assert.NotNil(ts.T(), merged.MergedAt)
+}
+
+func (ts *ServiceTestSuite) TestMergePR_BlockedByChangesRequested() {
+	ts.createTeam(&models.Team{TeamName: "backend", BlockOnChangesRequested: true}, "author", "reviewer1", "reviewer2")
+	pr := ts.createPR("pr-1", "author")
+
+	_, err := ts.service.SubmitReview(ts.ctx, &models.SubmitReviewRequest{
+		PullRequestID: pr.PullRequestID,
+		UserID:        pr.AssignedReviewers[0],
+		State:         models.ReviewChangesRequested,
+	})
+	require.NoError(ts.T(), err)
+
+	_, err = ts.service.MergePR(ts.ctx, pr.PullRequestID)
+	ts.assertBusinessError(err, ErrorMergeBlocked)
+
+	merged, err := ts.service.ForceMergePR(ts.ctx, pr.PullRequestID, &models.MergeOverride{Actor: "admin", Reason: "hotfix"})
+	require.NoError(ts.T(), err)
+	assert.Equal(ts.T(), models.PRStatusMerged, merged.Status)
+
+	overrides, err := ts.service.GetMergeOverrides(ts.ctx, pr.PullRequestID)
+	require.NoError(ts.T(), err)
+	assert.Len(ts.T(), overrides.Overrides, 1)
+}
+
+func (ts *ServiceTestSuite) TestReassignReviewer_PicksRemainingMember() {
+	ts.createTeam(&models.Team{TeamName: "backend"}, "author", "reviewer1", "reviewer2", "spare")
+	pr := ts.createPR("pr-1", "author")
+	old := pr.AssignedReviewers[0]
+
+	response, err := ts.service.ReassignReviewer(ts.ctx, pr.PullRequestID, old, false)
+	require.NoError(ts.T(), err)
+	assert.NotContains(ts.T(), response.PR.AssignedReviewers, old)
+	assert.NotContains(ts.T(), response.PR.AssignedReviewers, "author")
+	assert.Len(ts.T(), response.PR.AssignedReviewers, 2)
+
+	events, err := ts.service.GetAssignmentEvents(ts.ctx, pr.PullRequestID, old)
+	require.NoError(ts.T(), err)
+	assert.NotEmpty(ts.T(), events.Events)
+}
+
+func (ts *ServiceTestSuite) TestReassignReviewer_NoCandidate() {
+	ts.createTeam(&models.Team{TeamName: "backend"}, "author", "reviewer1", "reviewer2")
+	pr := ts.createPR("pr-1", "author")
+
+	_, err := ts.service.ReassignReviewer(ts.ctx, pr.PullRequestID, pr.AssignedReviewers[0], false)
+	ts.assertBusinessError(err, ErrorNoCandidate)
+
+	reviewers, err := ts.repo.GetPRReviewers(ts.ctx, pr.PullRequestID)
+	require.NoError(ts.T(), err)
+	assert.ElementsMatch(ts.T(), pr.AssignedReviewers, reviewers)
+}
+
+func (ts *ServiceTestSuite) TestReassignReviewer_NotAssigned() {
+	ts.createTeam(&models.Team{TeamName: "backend"}, "author", "reviewer1", "reviewer2", "spare")
+	pr := ts.createPR("pr-1", "author")
+
+	_, err := ts.service.ReassignReviewer(ts.ctx, pr.PullRequestID, "author", false)
+	ts.assertBusinessError(err, ErrorNotAssigned)
+}
+
+// firstCandidateStrategy picks candidates in user_id order, which makes selection predictable.
+type firstCandidateStrategy struct{}
+
+func (firstCandidateStrategy) Select(_ string, candidates []models.ReviewCandidate, count int) []string {
+	ids := candidateIDs(candidates)
+	sort.Strings(ids)
+	return ids[:limit(count, len(ids))]
+}
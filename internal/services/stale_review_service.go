@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+func (s *Service) GetStaleReviews(ctx context.Context, teamName string) (*models.StaleReviewsResponse, error) {
+	if teamName != "" {
+		exists, err := s.repo.TeamExists(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, NewBusinessError(ErrorNotFound, "team not found")
+		}
+	}
+
+	reviews, err := s.repo.GetStaleReviews(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StaleReviewsResponse{Reviews: reviews}, nil
+}
+
+// ProcessStaleReviews sends a single reminder for every overdue review and hands reviews
+// past the escalation threshold over to another reviewer through the reassign path. When no
+// replacement is found the attempt is recorded so it is retried one SLA period later, and the
+// reviewer is reminded instead.
+func (s *Service) ProcessStaleReviews(ctx context.Context) (*models.StaleReviewReport, error) {
+	reviews, err := s.repo.GetStaleReviews(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.StaleReviewReport{
+		Reminded:            []models.StaleReview{},
+		Escalated:           []models.ReassignedPRDetail{},
+		FailedReassignments: []models.FailedReassignment{},
+	}
+
+	for i := range reviews {
+		review := &reviews[i]
+
+		if review.EscalationDue {
+			response, err := s.reassignReviewer(ctx, review.PullRequestID, review.ReviewerID, false,
+				models.OperationEscalation, "review overdue")
+			bizErr, failed := err.(*BusinessError)
+			if err != nil && !failed {
+				return nil, err
+			}
+			if !failed {
+				report.Escalated = append(report.Escalated, models.ReassignedPRDetail{
+					PullRequestID: review.PullRequestID,
+					Replacements: []models.UserReplacement{{
+						OldUserID:    review.ReviewerID,
+						NewUserID:    response.ReplacedBy,
+						FallbackTeam: response.FallbackTeam,
+					}},
+				})
+				continue
+			}
+
+			report.FailedReassignments = append(report.FailedReassignments, models.FailedReassignment{
+				PullRequestID: review.PullRequestID,
+				OldUserID:     review.ReviewerID,
+				Reason:        bizErr.Message,
+			})
+			if err := s.repo.RecordEscalationAttempt(ctx, review); err != nil {
+				return nil, err
+			}
+		}
+
+		if review.RemindedAt != nil {
+			continue
+		}
+		reminded, err := s.repo.RecordReviewReminder(ctx, review)
+		if err != nil {
+			return nil, err
+		}
+		if reminded {
+			report.Reminded = append(report.Reminded, *review)
+		}
+	}
+
+	return report, nil
+}
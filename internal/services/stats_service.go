@@ -1,28 +1,101 @@
 package services
 
 import (
+	"context"
+
 	"github.com/lypolix/avito_test/internal/models"
 )
 
-func (s *Service) GetStats() (*models.StatsResponse, error) {
-	userStats, err := s.repo.GetUserAssignmentStats()
+func (s *Service) GetStats(ctx context.Context) (*models.StatsResponse, error) {
+	userStats, err := s.repo.GetUserAssignmentStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prStats, err := s.repo.GetPRAssignmentStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	prStats, err := s.repo.GetPRAssignmentStats()
+	summary, err := s.repo.GetStatsSummary(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	summary, err := s.repo.GetStatsSummary()
+	departmentStats, err := s.departmentStats(ctx, userStats, prStats)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.StatsResponse{
-		UserStats: userStats,
-		PRStats:   prStats,
-		Summary:   summary,
+		UserStats:       userStats,
+		PRStats:         prStats,
+		DepartmentStats: departmentStats,
+		Summary:         summary,
 	}, nil
 }
+
+// departmentStats groups user and PR stats by the top-level team of the team they belong to.
+func (s *Service) departmentStats(ctx context.Context, userStats []models.UserStat, prStats []models.PRStat) ([]models.DepartmentStat, error) {
+	teamNames, err := s.repo.GetTeamNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := s.repo.GetTeamParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	departments := []models.DepartmentStat{}
+	byName := make(map[string]int)
+	department := func(teamName string) *models.DepartmentStat {
+		root := rootTeam(teamName, parents)
+		i, ok := byName[root]
+		if !ok {
+			i = len(departments)
+			byName[root] = i
+			departments = append(departments, models.DepartmentStat{Department: root, Teams: []string{}})
+		}
+		return &departments[i]
+	}
+
+	for _, teamName := range teamNames {
+		stat := department(teamName)
+		stat.Teams = append(stat.Teams, teamName)
+	}
+	for _, userStat := range userStats {
+		if userStat.TeamName == "" {
+			continue
+		}
+		stat := department(userStat.TeamName)
+		stat.TotalUsers++
+		if userStat.IsActive {
+			stat.ActiveUsers++
+		}
+		stat.AssignmentsCount += userStat.AssignmentsCount
+		stat.OpenAssignmentsCount += userStat.OpenAssignmentsCount
+	}
+	for _, prStat := range prStats {
+		if prStat.TeamName == "" {
+			continue
+		}
+		stat := department(prStat.TeamName)
+		stat.TotalPRs++
+		if prStat.Status == models.PRStatusOpen {
+			stat.OpenPRs++
+		}
+	}
+
+	return departments, nil
+}
+
+func rootTeam(teamName string, parents map[string]string) string {
+	for depth := 0; depth < maxTeamDepth; depth++ {
+		parent, ok := parents[teamName]
+		if !ok {
+			break
+		}
+		teamName = parent
+	}
+	return teamName
+}
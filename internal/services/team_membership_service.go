@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+func (s *Service) AddTeamMembers(ctx context.Context, req *models.AddTeamMembersRequest) (*models.Team, error) {
+	if len(req.Members) == 0 {
+		return nil, NewBusinessError(ErrorInvalidTeam, "at least one member is required")
+	}
+	if err := normalizeMembers(req.Members); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, member := range req.Members {
+		existing, err := s.repo.GetUser(ctx, member.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.TeamName == req.TeamName {
+			return nil, NewBusinessError(ErrorInvalidTeam, "user "+member.UserID+" is already a member of the team")
+		}
+
+		added, err := s.repo.AddTeamMemberInTx(ctx, tx, &models.User{
+			UserID:         member.UserID,
+			Username:       member.Username,
+			TeamName:       req.TeamName,
+			IsActive:       member.IsActive,
+			ReviewWeight:   member.ReviewWeight,
+			MaxOpenReviews: member.MaxOpenReviews,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !added {
+			return nil, NewBusinessError(ErrorUserInOtherTeam, "user "+member.UserID+" already belongs to another team, move it instead")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(ctx, req.TeamName)
+}
+
+// RemoveTeamMembers deactivates the users and detaches them from the team. Their open
+// reviews are reassigned the same way bulk deactivation does it.
+func (s *Service) RemoveTeamMembers(ctx context.Context, teamName string, userIDs []string) (*models.RemoveTeamMembersResponse, error) {
+	team, err := s.repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
+	}
+
+	userIDs = uniqueStrings(userIDs)
+	for _, userID := range userIDs {
+		user, err := s.repo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || user.TeamName != teamName {
+			return nil, NewBusinessError(ErrorNotFound, "user not found in team: "+userID)
+		}
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reassignedPRs, failedReassignments, err := s.reassignReviewsOfUsersInTx(ctx, tx, team, userIDs,
+		models.OperationTeamRemove, "reviewer removed from team")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range userIDs {
+		if err := s.repo.UpdateUserActiveInTx(ctx, tx, userID, false); err != nil {
+			return nil, err
+		}
+		if err := s.repo.DetachUserInTx(ctx, tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.RemoveTeamMembersResponse{
+		TeamName:            teamName,
+		RemovedUsers:        userIDs,
+		ReassignedPRs:       reassignedPRs,
+		FailedReassignments: failedReassignments,
+	}, nil
+}
+
+// MoveTeamMember transfers the user to another team. Reviews the user holds are reassigned
+// within the previous team first, since they were picked from that team's pool.
+func (s *Service) MoveTeamMember(ctx context.Context, userID, teamName string) (*models.MoveTeamMemberResponse, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+	if user.TeamName == teamName {
+		return nil, NewBusinessError(ErrorInvalidTeam, "user already belongs to team "+teamName)
+	}
+
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	response := &models.MoveTeamMemberResponse{
+		UserID:              userID,
+		FromTeam:            user.TeamName,
+		ToTeam:              teamName,
+		ReassignedPRs:       []models.ReassignedPRDetail{},
+		FailedReassignments: []models.FailedReassignment{},
+	}
+
+	if user.TeamName != "" {
+		team, err := s.repo.GetTeamSettings(ctx, user.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		response.ReassignedPRs, response.FailedReassignments, err = s.reassignReviewsOfUsersInTx(ctx, tx, team, []string{userID},
+			models.OperationTeamMove, "reviewer moved to team "+teamName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.MoveUserInTx(ctx, tx, userID, teamName); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RenameTeam reports a taken new name from the unique key of the teams table, so two
+// concurrent renames to the same name cannot both pass.
+func (s *Service) RenameTeam(ctx context.Context, teamName, newName string) (*models.Team, error) {
+	if newName == teamName {
+		return nil, NewBusinessError(ErrorInvalidTeam, "new_name must differ from team_name")
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	team, err := s.repo.LockTeamInTx(ctx, tx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	err = s.repo.RenameTeamInTx(ctx, tx, teamName, newName)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, NewBusinessError(ErrorTeamExists, "team_name already exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(ctx, newName)
+}
+
+func (s *Service) DeleteTeam(ctx context.Context, teamName string) error {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	deleted, err := s.repo.DeleteTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return NewBusinessError(ErrorTeamNotEmpty, "team still has members or sub-teams")
+	}
+	return nil
+}
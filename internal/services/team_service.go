@@ -1,20 +1,201 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
 )
 
-func (s *Service) CreateTeam(team *models.Team) error {
-	exists, err := s.repo.TeamExists(team.TeamName)
+// maxTeamDepth limits how many levels the team hierarchy may have.
+const maxTeamDepth = 32
+
+// CreateTeam stores the team, its fallbacks and its members in one transaction, so a failure
+// leaves nothing behind. Users without a team are taken into the new team. A taken name is
+// reported from the unique key of the teams table, so two concurrent requests cannot both pass.
+func (s *Service) CreateTeam(ctx context.Context, team *models.Team) error {
+	if err := s.prepareTeam(ctx, team); err != nil {
+		return err
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
 		return err
 	}
-	if exists {
+	defer tx.Rollback()
+
+	if err := s.validateFallbackTeams(ctx, tx, team.TeamName, team.FallbackTeams); err != nil {
+		return err
+	}
+	if err := s.validateParentTeam(ctx, tx, team); err != nil {
+		return err
+	}
+
+	err = s.repo.CreateTeamInTx(ctx, tx, team)
+	if errors.Is(err, repository.ErrAlreadyExists) {
 		return NewBusinessError(ErrorTeamExists, "team_name already exists")
 	}
+	if err != nil {
+		return err
+	}
+
+	if len(team.FallbackTeams) > 0 {
+		if err := s.repo.SetTeamFallbacksInTx(ctx, tx, team.TeamName, team.FallbackTeams); err != nil {
+			return err
+		}
+	} else {
+		team.FallbackTeams = []string{}
+	}
+
+	for _, member := range team.Members {
+		if err := s.addTeamMemberInTx(ctx, tx, team.TeamName, member); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SyncTeam brings an existing team in line with the definition: missing members are added,
+// and existing ones get the username and active flag from the definition. Open reviews of
+// members it deactivates are reassigned as in BulkDeactivateUsers. Members left out of the
+// definition and the team settings stay as they are. A missing team is created as by CreateTeam.
+func (s *Service) SyncTeam(ctx context.Context, team *models.Team) (*models.TeamSyncResponse, error) {
+	exists, err := s.repo.TeamExists(ctx, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.TeamSyncResponse{
+		Created:          !exists,
+		AddedMembers:     []string{},
+		UpdatedMembers:   []models.TeamMemberChange{},
+		UnchangedMembers: []string{},
+		ReassignedPRs:    []models.ReassignedPRDetail{},
+	}
+
+	if !exists {
+		if err := s.CreateTeam(ctx, team); err != nil {
+			return nil, err
+		}
+		for _, member := range team.Members {
+			response.AddedMembers = append(response.AddedMembers, member.UserID)
+		}
+	} else if err := s.syncTeamMembers(ctx, team, response); err != nil {
+		return nil, err
+	}
+
+	response.Team, err = s.GetTeam(ctx, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *Service) syncTeamMembers(ctx context.Context, team *models.Team, response *models.TeamSyncResponse) error {
+	if err := normalizeMembers(team.Members); err != nil {
+		return err
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deactivated []string
+	for _, member := range team.Members {
+		existing, err := s.repo.LockUserInTx(ctx, tx, member.UserID)
+		if err != nil {
+			return err
+		}
+		if existing == nil || existing.TeamName != team.TeamName {
+			if err := s.addTeamMemberInTx(ctx, tx, team.TeamName, member); err != nil {
+				return err
+			}
+			response.AddedMembers = append(response.AddedMembers, member.UserID)
+			continue
+		}
+
+		changed := []string{}
+		if existing.Username != member.Username {
+			if err := s.repo.UpdateUsernameInTx(ctx, tx, member.UserID, member.Username); err != nil {
+				return err
+			}
+			changed = append(changed, "username")
+		}
+		if existing.IsActive != member.IsActive {
+			if err := s.repo.UpdateUserActiveInTx(ctx, tx, member.UserID, member.IsActive); err != nil {
+				return err
+			}
+			changed = append(changed, "is_active")
+			if !member.IsActive {
+				deactivated = append(deactivated, member.UserID)
+			}
+		}
+
+		if len(changed) == 0 {
+			response.UnchangedMembers = append(response.UnchangedMembers, member.UserID)
+			continue
+		}
+		response.UpdatedMembers = append(response.UpdatedMembers, models.TeamMemberChange{
+			UserID:  member.UserID,
+			Changed: changed,
+		})
+	}
+
+	if len(deactivated) > 0 {
+		settings, err := s.repo.GetTeamSettings(ctx, team.TeamName)
+		if err != nil {
+			return err
+		}
+		response.ReassignedPRs, response.FailedReassignments, err = s.reassignReviewsOfUsersInTx(ctx, tx, settings,
+			deactivated, models.OperationBulkDeactivate, "reviewer deactivated by team sync")
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// addTeamMemberInTx creates the member or takes over a user without a team. A user of
+// another team is refused, even if it joined that team after the caller's checks.
+func (s *Service) addTeamMemberInTx(ctx context.Context, tx repository.Tx, teamName string, member models.TeamMember) error {
+	added, err := s.repo.AddTeamMemberInTx(ctx, tx, &models.User{
+		UserID:         member.UserID,
+		Username:       member.Username,
+		TeamName:       teamName,
+		IsActive:       member.IsActive,
+		ReviewWeight:   member.ReviewWeight,
+		MaxOpenReviews: member.MaxOpenReviews,
+	})
+	if err != nil {
+		return err
+	}
+	if !added {
+		return NewBusinessError(ErrorUserInOtherTeam, "user "+member.UserID+" already belongs to another team")
+	}
+	return nil
+}
+
+// prepareTeam fills in the defaults of a new team and validates its settings and members.
+// Fallbacks and the parent are checked by CreateTeam inside its transaction.
+func (s *Service) prepareTeam(ctx context.Context, team *models.Team) error {
+	if team.ReviewerStrategy == "" {
+		team.ReviewerStrategy = DefaultStrategy
+	}
+	if team.MaxReviewers == 0 {
+		team.MaxReviewers = DefaultMaxReviewers
+	}
+	if err := s.validateTeamSettings(team); err != nil {
+		return err
+	}
 
 	for _, member := range team.Members {
-		inOtherTeam, err := s.repo.IsUserInOtherTeam(member.UserID, team.TeamName)
+		inOtherTeam, err := s.repo.IsUserInOtherTeam(ctx, member.UserID, team.TeamName)
 		if err != nil {
 			return err
 		}
@@ -22,27 +203,200 @@ func (s *Service) CreateTeam(team *models.Team) error {
 			return NewBusinessError(ErrorUserInOtherTeam, "user "+member.UserID+" already belongs to another team")
 		}
 	}
-	if err := s.repo.CreateTeam(team.TeamName); err != nil {
+
+	return normalizeMembers(team.Members)
+}
+
+// UpdateTeam changes the team's own settings. It starts from the stored row rather than the
+// effective settings, so values inherited from an ancestor are never written into the team.
+// The row is locked and the parent and fallbacks are checked in the same transaction that
+// writes them, so a concurrent update cannot slip a cycle or a deleted team in between.
+func (s *Service) UpdateTeam(ctx context.Context, req *models.UpdateTeamRequest) (*models.Team, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	team, err := s.repo.LockTeamInTx(ctx, tx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	if req.ParentTeam != nil {
+		team.ParentTeam = *req.ParentTeam
+	}
+	if req.InheritPolicy != nil {
+		team.InheritPolicy = *req.InheritPolicy
+	}
+	if team.InheritPolicy && updatesPolicy(req) {
+		return nil, NewBusinessError(ErrorInvalidTeam, "team inherits its policy, set inherit_policy to false to change it")
+	}
+	if err := s.validateParentTeam(ctx, tx, team); err != nil {
+		return nil, err
+	}
+
+	if req.ReviewerStrategy != nil {
+		team.ReviewerStrategy = *req.ReviewerStrategy
+	}
+	if req.MinReviewers != nil {
+		team.MinReviewers = *req.MinReviewers
+	}
+	if req.MaxReviewers != nil {
+		team.MaxReviewers = *req.MaxReviewers
+	}
+	if req.RequiredApprovals != nil {
+		team.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.BlockOnChangesRequested != nil {
+		team.BlockOnChangesRequested = *req.BlockOnChangesRequested
+	}
+	if req.RequireOwnerApproval != nil {
+		team.RequireOwnerApproval = *req.RequireOwnerApproval
+	}
+	if req.ReviewSLAMinutes != nil {
+		team.ReviewSLAMinutes = *req.ReviewSLAMinutes
+	}
+	if req.ReviewEscalationMinutes != nil {
+		team.ReviewEscalationMinutes = *req.ReviewEscalationMinutes
+	}
+	if err := s.validateTeamSettings(team); err != nil {
+		return nil, err
+	}
+
+	if req.FallbackTeams != nil {
+		if err := s.validateFallbackTeams(ctx, tx, team.TeamName, req.FallbackTeams); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateTeamSettingsInTx(ctx, tx, team); err != nil {
+		return nil, err
+	}
+
+	if req.FallbackTeams != nil {
+		if err := s.repo.SetTeamFallbacksInTx(ctx, tx, team.TeamName, req.FallbackTeams); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetTeam(ctx, req.TeamName)
+}
+
+func (s *Service) validateTeamSettings(team *models.Team) error {
+	if _, ok := s.strategies[team.ReviewerStrategy]; !ok {
+		return NewBusinessError(ErrorInvalidStrategy, "unknown reviewer_strategy: "+team.ReviewerStrategy)
+	}
+	if team.MinReviewers < 0 || team.MaxReviewers < 1 || team.MaxReviewers > MaxReviewersLimit {
+		return NewBusinessError(ErrorInvalidTeam, fmt.Sprintf("max_reviewers must be between 1 and %d, min_reviewers must not be negative", MaxReviewersLimit))
+	}
+	if team.MinReviewers > team.MaxReviewers {
+		return NewBusinessError(ErrorInvalidTeam, "min_reviewers must not exceed max_reviewers")
+	}
+	if team.RequiredApprovals < 0 || team.RequiredApprovals > MaxReviewersLimit {
+		return NewBusinessError(ErrorInvalidTeam, fmt.Sprintf("required_approvals must be between 0 and %d", MaxReviewersLimit))
+	}
+	if team.ReviewSLAMinutes < 0 || team.ReviewEscalationMinutes < 0 {
+		return NewBusinessError(ErrorInvalidTeam, "review_sla_minutes and review_escalation_minutes must not be negative")
+	}
+	if team.ReviewEscalationMinutes > 0 && (team.ReviewSLAMinutes == 0 || team.ReviewEscalationMinutes <= team.ReviewSLAMinutes) {
+		return NewBusinessError(ErrorInvalidTeam, "review_escalation_minutes requires review_sla_minutes and must exceed it")
+	}
+	return nil
+}
+
+func normalizeMembers(members []models.TeamMember) error {
+	seen := make(map[string]bool, len(members))
+	for i := range members {
+		if seen[members[i].UserID] {
+			return NewBusinessError(ErrorInvalidTeam, "user "+members[i].UserID+" is listed more than once")
+		}
+		seen[members[i].UserID] = true
+
+		if members[i].ReviewWeight < 0 {
+			return NewBusinessError(ErrorInvalidTeam, "review_weight must be positive for user "+members[i].UserID)
+		}
+		if members[i].MaxOpenReviews != nil && *members[i].MaxOpenReviews < 0 {
+			return NewBusinessError(ErrorInvalidTeam, "max_open_reviews must not be negative for user "+members[i].UserID)
+		}
+		if members[i].ReviewWeight == 0 {
+			members[i].ReviewWeight = 1
+		}
+	}
+	return nil
+}
+
+func updatesPolicy(req *models.UpdateTeamRequest) bool {
+	return req.ReviewerStrategy != nil || req.MinReviewers != nil || req.MaxReviewers != nil ||
+		req.RequiredApprovals != nil || req.BlockOnChangesRequested != nil || req.RequireOwnerApproval != nil ||
+		req.ReviewSLAMinutes != nil || req.ReviewEscalationMinutes != nil
+}
+
+// validateParentTeam rejects parents that do not exist or would turn the hierarchy into a cycle.
+// The parent stays locked until tx ends, so it cannot be deleted or moved under the team meanwhile.
+func (s *Service) validateParentTeam(ctx context.Context, tx repository.Tx, team *models.Team) error {
+	if team.ParentTeam == "" {
+		if team.InheritPolicy {
+			return NewBusinessError(ErrorInvalidTeam, "inherit_policy requires parent_team")
+		}
+		return nil
+	}
+	if team.ParentTeam == team.TeamName {
+		return NewBusinessError(ErrorInvalidTeam, "team cannot be its own parent")
+	}
+
+	parent, err := s.repo.LockTeamInTx(ctx, tx, team.ParentTeam)
+	if err != nil {
 		return err
 	}
+	if parent == nil {
+		return NewBusinessError(ErrorNotFound, "parent team not found: "+team.ParentTeam)
+	}
 
-	for _, member := range team.Members {
-		user := &models.User{
-			UserID:   member.UserID,
-			Username: member.Username,
-			TeamName: team.TeamName,
-			IsActive: member.IsActive,
+	ancestors, err := s.repo.GetTeamAncestorsInTx(ctx, tx, team.ParentTeam)
+	if err != nil {
+		return err
+	}
+	if contains(ancestors, team.TeamName) {
+		return NewBusinessError(ErrorInvalidTeam, "parent_team would create a cycle")
+	}
+	if len(ancestors)+1 >= maxTeamDepth {
+		return NewBusinessError(ErrorInvalidTeam, fmt.Sprintf("team hierarchy must not exceed %d levels", maxTeamDepth))
+	}
+	return nil
+}
+
+func (s *Service) validateFallbackTeams(ctx context.Context, tx repository.Tx, teamName string, fallbacks []string) error {
+	seen := make(map[string]bool)
+	for _, fallback := range fallbacks {
+		if fallback == teamName {
+			return NewBusinessError(ErrorInvalidTeam, "team cannot be its own fallback")
+		}
+		if seen[fallback] {
+			return NewBusinessError(ErrorInvalidTeam, "duplicate fallback team: "+fallback)
 		}
-		if err := s.repo.CreateUser(user); err != nil {
+		seen[fallback] = true
+
+		exists, err := s.repo.TeamExistsInTx(ctx, tx, fallback)
+		if err != nil {
 			return err
 		}
+		if !exists {
+			return NewBusinessError(ErrorNotFound, "fallback team not found: "+fallback)
+		}
 	}
-
 	return nil
 }
 
-func (s *Service) GetTeam(teamName string) (*models.Team, error) {
-	team, err := s.repo.GetTeam(teamName)
+func (s *Service) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := s.repo.GetTeam(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -51,3 +405,36 @@ func (s *Service) GetTeam(teamName string) (*models.Team, error) {
 	}
 	return team, nil
 }
+
+// GetTeamTree returns the team with its sub-teams nested recursively. AggregatedMembers
+// lists the members of the team and of every team below it.
+func (s *Service) GetTeamTree(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := s.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	return s.fillTeamTree(ctx, team)
+}
+
+func (s *Service) fillTeamTree(ctx context.Context, team *models.Team) (*models.Team, error) {
+	subTeams, err := s.repo.GetSubTeams(ctx, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	team.SubTeams = []models.Team{}
+	team.AggregatedMembers = append([]models.TeamMember{}, team.Members...)
+	for _, subTeamName := range subTeams {
+		subTeam, err := s.GetTeam(ctx, subTeamName)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.fillTeamTree(ctx, subTeam); err != nil {
+			return nil, err
+		}
+		team.SubTeams = append(team.SubTeams, *subTeam)
+		team.AggregatedMembers = append(team.AggregatedMembers, subTeam.AggregatedMembers...)
+	}
+
+	return team, nil
+}
@@ -1,13 +1,16 @@
 package services
 
 import (
-	"database/sql"
+	"context"
+	"log/slog"
 
+	"github.com/lypolix/avito_test/internal/logging"
 	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
 )
 
-func (s *Service) SetUserActive(userID string, isActive bool) (*models.User, error) {
-	user, err := s.repo.GetUser(userID)
+func (s *Service) SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
+	user, err := s.repo.GetUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -15,11 +18,11 @@ func (s *Service) SetUserActive(userID string, isActive bool) (*models.User, err
 		return nil, NewBusinessError(ErrorNotFound, "resource not found")
 	}
 
-	if err := s.repo.UpdateUserActive(userID, isActive); err != nil {
+	if err := s.repo.UpdateUserActive(ctx, userID, isActive); err != nil {
 		return nil, err
 	}
 
-	updatedUser, err := s.repo.GetUser(userID)
+	updatedUser, err := s.repo.GetUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -27,8 +30,28 @@ func (s *Service) SetUserActive(userID string, isActive bool) (*models.User, err
 	return updatedUser, nil
 }
 
-func (s *Service) GetUserPRs(userID string) (*models.UserPRsResponse, error) {
-	user, err := s.repo.GetUser(userID)
+func (s *Service) SetUserReviewLimit(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error) {
+	if maxOpenReviews != nil && *maxOpenReviews < 0 {
+		return nil, NewBusinessError(ErrorInvalidLimit, "max_open_reviews must not be negative")
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, NewBusinessError(ErrorNotFound, "resource not found")
+	}
+
+	if err := s.repo.UpdateUserReviewLimit(ctx, userID, maxOpenReviews); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUser(ctx, userID)
+}
+
+func (s *Service) GetUserPRs(ctx context.Context, userID string) (*models.UserPRsResponse, error) {
+	user, err := s.repo.GetUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +59,7 @@ func (s *Service) GetUserPRs(userID string) (*models.UserPRsResponse, error) {
 		return nil, NewBusinessError(ErrorNotFound, "resource not found")
 	}
 
-	prs, err := s.repo.GetPRsByReviewer(userID)
+	prs, err := s.repo.GetPRsByReviewer(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -47,14 +70,16 @@ func (s *Service) GetUserPRs(userID string) (*models.UserPRsResponse, error) {
 	}, nil
 }
 
-func (s *Service) BulkDeactivateUsers(teamName string, userIDs []string) (*models.BulkDeactivateResponse, error) {
-	tx, err := s.repo.BeginTx()
+func (s *Service) BulkDeactivateUsers(ctx context.Context, teamName string, userIDs []string) (*models.BulkDeactivateResponse, error) {
+	ctx = logging.With(ctx, "team_name", teamName, "user_ids", userIDs)
+
+	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	response, err := s.processBulkDeactivationInTx(tx, teamName, userIDs)
+	response, err := s.processBulkDeactivationInTx(ctx, tx, teamName, userIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -63,28 +88,29 @@ func (s *Service) BulkDeactivateUsers(teamName string, userIDs []string) (*model
 		return nil, err
 	}
 
+	slog.InfoContext(ctx, "users deactivated", "reassigned_prs", len(response.ReassignedPRs),
+		"failed_reassignments", len(response.FailedReassignments))
 	return response, nil
 }
 
-func (s *Service) processBulkDeactivationInTx(tx *sql.Tx, teamName string, userIDs []string) (*models.BulkDeactivateResponse, error) {
-	teamExists, err := s.repo.TeamExists(teamName)
+func (s *Service) processBulkDeactivationInTx(ctx context.Context, tx repository.Tx, teamName string, userIDs []string) (*models.BulkDeactivateResponse, error) {
+	team, err := s.repo.GetTeamSettings(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
-	if !teamExists {
+	if team == nil {
 		return nil, NewBusinessError(ErrorNotFound, "team not found")
 	}
 
-	activeTeamUsers, err := s.repo.GetActiveUsersByTeamInTx(tx, teamName)
+	// Candidates leave out absent users, so membership is checked against the team itself.
+	activeUsers, err := s.repo.GetActiveUsersByTeamInTx(ctx, tx, teamName)
 	if err != nil {
 		return nil, err
 	}
 
 	activeUsersMap := make(map[string]bool)
-	var activeUserIDs []string
-	for _, user := range activeTeamUsers {
+	for _, user := range activeUsers {
 		activeUsersMap[user.UserID] = true
-		activeUserIDs = append(activeUserIDs, user.UserID)
 	}
 
 	for _, userID := range userIDs {
@@ -93,62 +119,104 @@ func (s *Service) processBulkDeactivationInTx(tx *sql.Tx, teamName string, userI
 		}
 	}
 
+	reassignedPRs, failedReassignments, err := s.reassignReviewsOfUsersInTx(ctx, tx, team, userIDs,
+		models.OperationBulkDeactivate, "reviewer deactivated")
+	if err != nil {
+		return nil, err
+	}
+
 	response := &models.BulkDeactivateResponse{
 		TeamName:            teamName,
 		DeactivatedUsers:    userIDs,
-		ReassignedPRs:       []models.ReassignedPRDetail{},
-		FailedReassignments: []models.FailedReassignment{},
+		ReassignedPRs:       reassignedPRs,
+		FailedReassignments: failedReassignments,
 	}
 
-	openPRs, err := s.repo.GetAllOpenPRs()
-	if err != nil {
-		return nil, err
+	for _, userID := range userIDs {
+		if err := s.repo.UpdateUserActiveInTx(ctx, tx, userID, false); err != nil {
+			return nil, err
+		}
 	}
 
-	deactivatingMap := make(map[string]bool)
-	for _, userID := range userIDs {
-		deactivatingMap[userID] = true
+	return response, nil
+}
+
+// reassignReviewsOfUsersInTx hands every open review of the given users to other candidates,
+// searching the team, its fallback teams and its ancestors, as done when the users leave the reviewer pool.
+func (s *Service) reassignReviewsOfUsersInTx(ctx context.Context, tx repository.Tx, team *models.Team, userIDs []string, operation, reason string) ([]models.ReassignedPRDetail, []models.FailedReassignment, error) {
+	reassignedPRs := []models.ReassignedPRDetail{}
+	failedReassignments := []models.FailedReassignment{}
+
+	prs, err := s.openPRsReviewedBy(ctx, userIDs)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var prsToProcess []*models.PullRequest
-	for _, pr := range openPRs {
-		for _, reviewer := range pr.AssignedReviewers {
-			if deactivatingMap[reviewer] {
-				prsToProcess = append(prsToProcess, pr)
-				break
-			}
+	pool := s.candidatePoolInTx(tx)
+	tiers := teamTiers(team)
+	for _, listed := range prs {
+		// The list is read without locks, so each PR is locked and read again in tx;
+		// a PR merged or reassigned meanwhile is taken as it is now.
+		status, err := s.repo.LockPRInTx(ctx, tx, listed.PullRequestID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if status != models.PRStatusOpen {
+			continue
+		}
+		pr, err := s.repo.GetPRInTx(ctx, tx, listed.PullRequestID)
+		if err != nil {
+			return nil, nil, err
 		}
-	}
 
-	for _, pr := range prsToProcess {
-		reassignedPR, failedReplacements, err := s.reassignDeactivatedReviewersInTx(tx, pr, userIDs, activeUserIDs)
+		reassignedPR, failedReplacements, err := s.reassignDeactivatedReviewersInTx(ctx, tx, pr, userIDs, team, tiers, pool, operation, reason)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if len(reassignedPR.Replacements) > 0 || len(failedReplacements) > 0 {
-			response.ReassignedPRs = append(response.ReassignedPRs, reassignedPR)
+			reassignedPRs = append(reassignedPRs, reassignedPR)
 		}
+		failedReassignments = append(failedReassignments, failedReplacements...)
+	}
+
+	return reassignedPRs, failedReassignments, nil
+}
 
-		response.FailedReassignments = append(response.FailedReassignments, failedReplacements...)
+func (s *Service) openPRsReviewedBy(ctx context.Context, userIDs []string) ([]*models.PullRequest, error) {
+	openPRs, err := s.repo.GetAllOpenPRs(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	userMap := make(map[string]bool)
 	for _, userID := range userIDs {
-		if err := s.repo.UpdateUserActiveInTx(tx, userID, false); err != nil {
-			return nil, err
-		}
+		userMap[userID] = true
 	}
 
-	return response, nil
+	var prs []*models.PullRequest
+	for _, pr := range openPRs {
+		for _, reviewer := range pr.AssignedReviewers {
+			if userMap[reviewer] {
+				prs = append(prs, pr)
+				break
+			}
+		}
+	}
+	return prs, nil
 }
 
-func (s *Service) reassignDeactivatedReviewersInTx(tx *sql.Tx, pr *models.PullRequest, deactivatingUserIDs []string, activeUserIDs []string) (models.ReassignedPRDetail, []models.FailedReassignment, error) {
+// reassignDeactivatedReviewersInTx replaces the given reviewers on a PR, recording the
+// changes in the audit log under operation with reason explaining why they left.
+func (s *Service) reassignDeactivatedReviewersInTx(ctx context.Context, tx repository.Tx, pr *models.PullRequest, deactivatingUserIDs []string, team *models.Team, tiers []string, pool *candidatePool, operation, reason string) (models.ReassignedPRDetail, []models.FailedReassignment, error) {
 	reassignedPR := models.ReassignedPRDetail{
 		PullRequestID: pr.PullRequestID,
 		Replacements:  []models.UserReplacement{},
 	}
 
 	var failedReplacements []models.FailedReassignment
+	var events []models.AssignmentEvent
+	fallbackTeams := make(map[string]string)
 
 	newReviewers := make([]string, len(pr.AssignedReviewers))
 	copy(newReviewers, pr.AssignedReviewers)
@@ -158,62 +226,58 @@ func (s *Service) reassignDeactivatedReviewersInTx(tx *sql.Tx, pr *models.PullRe
 		currentReviewersMap[reviewer] = true
 	}
 
-	deactivatingMap := make(map[string]bool)
+	exclude := map[string]bool{pr.AuthorID: true}
+	for _, reviewer := range newReviewers {
+		exclude[reviewer] = true
+	}
 	for _, userID := range deactivatingUserIDs {
-		deactivatingMap[userID] = true
+		exclude[userID] = true
 	}
 
-	availableCandidates := s.findAvailableCandidates(activeUserIDs, pr.AuthorID, currentReviewersMap, deactivatingMap)
-
 	for _, oldUserID := range deactivatingUserIDs {
 		if !currentReviewersMap[oldUserID] {
 			continue
 		}
 
-		if len(availableCandidates) == 0 {
+		selected, err := s.selectReviewers(ctx, team, tiers, pool, exclude, 1)
+		if err != nil {
+			return reassignedPR, failedReplacements, err
+		}
+		if len(selected) == 0 {
 			newReviewers = s.removeFromSlice(newReviewers, oldUserID)
 			failedReplacements = append(failedReplacements, models.FailedReassignment{
 				PullRequestID: pr.PullRequestID,
 				OldUserID:     oldUserID,
 				Reason:        "no active replacement candidate available",
 			})
+			events = append(events, removedEvent(pr.PullRequestID, operation, oldUserID,
+				reason+", no active replacement candidate available"))
 			continue
 		}
 
-		newUserID := availableCandidates[0]
-		availableCandidates = availableCandidates[1:]
-
-		newReviewers = s.replaceInSlice(newReviewers, oldUserID, newUserID)
-
-		availableCandidates = s.removeFromSlice(availableCandidates, newUserID)
+		newReviewers = s.replaceInSlice(newReviewers, oldUserID, selected[0].UserID)
+		if selected[0].FallbackTeam != "" {
+			fallbackTeams[selected[0].UserID] = selected[0].FallbackTeam
+		}
+		events = append(events, replacedEvent(pr.PullRequestID, operation, oldUserID, selected[0], reason))
 
 		reassignedPR.Replacements = append(reassignedPR.Replacements, models.UserReplacement{
-			OldUserID: oldUserID,
-			NewUserID: newUserID,
+			OldUserID:    oldUserID,
+			NewUserID:    selected[0].UserID,
+			FallbackTeam: selected[0].FallbackTeam,
 		})
 	}
 
 	if len(reassignedPR.Replacements) > 0 || len(failedReplacements) > 0 {
-		if err := s.repo.UpdatePRReviewersInTx(tx, pr.PullRequestID, newReviewers); err != nil {
+		if err := s.repo.UpdatePRReviewersInTx(ctx, tx, pr.PullRequestID, newReviewers, fallbackTeams); err != nil {
 			return reassignedPR, failedReplacements, err
 		}
-	}
-
-	return reassignedPR, failedReplacements, nil
-}
-
-func (s *Service) findAvailableCandidates(activeUserIDs []string, authorID string, currentReviewersMap map[string]bool, deactivatingMap map[string]bool) []string {
-	var candidates []string
-
-	for _, userID := range activeUserIDs {
-		if userID != authorID &&
-			!currentReviewersMap[userID] &&
-			!deactivatingMap[userID] {
-			candidates = append(candidates, userID)
+		if err := s.repo.CreateAssignmentEventsInTx(ctx, tx, events); err != nil {
+			return reassignedPR, failedReplacements, err
 		}
 	}
 
-	return candidates
+	return reassignedPR, failedReplacements, nil
 }
 
 func (s *Service) removeFromSlice(slice []string, item string) []string {
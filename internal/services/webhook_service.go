@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+)
+
+var webhookEventTypes = map[string]bool{
+	models.EventPRCreated:          true,
+	models.EventPROpened:           true,
+	models.EventPRMerged:           true,
+	models.EventPRClosed:           true,
+	models.EventReviewerAssigned:   true,
+	models.EventReviewerReassigned: true,
+	models.EventReviewerRemoved:    true,
+	models.EventUserDeactivated:    true,
+	models.EventReviewReminder:     true,
+}
+
+func (s *Service) RegisterWebhook(ctx context.Context, req *models.RegisterWebhookRequest) (*models.Webhook, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	if len(req.EventTypes) == 0 {
+		return nil, NewBusinessError(ErrorInvalidWebhook, "at least one event type is required")
+	}
+	for _, eventType := range req.EventTypes {
+		if !webhookEventTypes[eventType] {
+			return nil, NewBusinessError(ErrorInvalidWebhook, "unknown event type: "+eventType)
+		}
+	}
+
+	exists, err := s.repo.TeamExists(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.Webhook{
+		TeamName:   req.TeamName,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: uniqueStrings(req.EventTypes),
+		IsActive:   true,
+	}
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *Service) GetWebhooks(ctx context.Context, teamName string) (*models.WebhooksResponse, error) {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewBusinessError(ErrorNotFound, "team not found")
+	}
+
+	webhooks, err := s.repo.GetWebhooksByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The secret is only shown once, when the webhook is registered.
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return &models.WebhooksResponse{
+		TeamName: teamName,
+		Webhooks: webhooks,
+	}, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, webhookID int) error {
+	deleted, err := s.repo.DeleteWebhook(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return NewBusinessError(ErrorNotFound, "webhook not found")
+	}
+	return nil
+}
+
+func (s *Service) GetWebhookDeliveries(ctx context.Context, webhookID int, status string) (*models.WebhookDeliveriesResponse, error) {
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+	default:
+		return nil, NewBusinessError(ErrorInvalidWebhook, "unknown delivery status: "+status)
+	}
+
+	webhook, err := s.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, NewBusinessError(ErrorNotFound, "webhook not found")
+	}
+
+	deliveries, err := s.repo.GetWebhookDeliveries(ctx, webhookID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookDeliveriesResponse{
+		WebhookID:  webhookID,
+		Deliveries: deliveries,
+	}, nil
+}
+
+// ReplayDelivery queues a new delivery with the same payload as the given one,
+// regardless of whether the original succeeded.
+func (s *Service) ReplayDelivery(ctx context.Context, deliveryID int64) (*models.WebhookDelivery, error) {
+	original, err := s.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, NewBusinessError(ErrorNotFound, "delivery not found")
+	}
+
+	replay := &models.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: time.Now().UTC(),
+		ReplayOf:      &original.ID,
+	}
+	if _, err := s.repo.CreateWebhookDelivery(ctx, replay); err != nil {
+		return nil, err
+	}
+
+	return replay, nil
+}
+
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return NewBusinessError(ErrorInvalidWebhook, "webhook url must be an absolute http(s) url")
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
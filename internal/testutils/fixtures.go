@@ -0,0 +1,186 @@
+package testutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/database"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+	"github.com/lypolix/avito_test/internal/repository/memory"
+)
+
+// fixtures writes test data straight into the storage backend, bypassing the service
+// and without queuing outbox events.
+type fixtures interface {
+	clean(t *testing.T)
+	createTeam(t *testing.T, teamName string)
+	createUser(t *testing.T, userID, username, teamName string, isActive bool)
+	createPR(t *testing.T, prID, prName, authorID string, reviewers []string)
+	ageAssignment(t *testing.T, prID, userID string, minutes int)
+}
+
+// openStore returns the backend selected by cfg.Driver, which the suites take from
+// TEST_STORAGE. The returned DB is nil for the in-memory store.
+func openStore(t *testing.T, cfg config.DatabaseConfig) (*sql.DB, repository.Store, fixtures) {
+	t.Helper()
+
+	switch cfg.Driver {
+	case config.DriverMemory:
+		store := memory.New()
+		return nil, store, memoryFixtures{store: store}
+	case config.DriverSQLite:
+		if cfg.Path == "" {
+			cfg.Path = filepath.Join(t.TempDir(), "test.db")
+		}
+		db, err := database.OpenSQLite(cfg)
+		if err != nil {
+			t.Fatalf("Failed to open test database: %v", err)
+		}
+		return db, repository.NewSQLiteRepository(db), sqlFixtures{db: db, sqlite: true}
+	}
+
+	db, err := database.ConnectWithRetry(cfg)
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	return db, repository.NewRepository(db), sqlFixtures{db: db}
+}
+
+type sqlFixtures struct {
+	db     *sql.DB
+	sqlite bool
+}
+
+func (f sqlFixtures) clean(t *testing.T) {
+	t.Helper()
+	tables := []string{"idempotency_keys", "user_absences", "external_accounts", "webhook_deliveries", "webhooks", "outbox_events", "assignment_events", "merge_overrides", "pr_reviews", "pr_files", "ownership_rules", "pr_reviewers", "pull_requests", "team_fallbacks", "users", "teams"}
+	for _, table := range tables {
+		_, err := f.db.Exec(fmt.Sprintf("DELETE FROM %s", table))
+		if err != nil {
+			t.Fatalf("Failed to clean table %s: %v", table, err)
+		}
+	}
+}
+
+func (f sqlFixtures) createTeam(t *testing.T, teamName string) {
+	t.Helper()
+	_, err := f.db.Exec("INSERT INTO teams (team_name) VALUES ($1)", teamName)
+	if err != nil {
+		t.Fatalf("Failed to create test team: %v", err)
+	}
+}
+
+func (f sqlFixtures) createUser(t *testing.T, userID, username, teamName string, isActive bool) {
+	t.Helper()
+	_, err := f.db.Exec(
+		"INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, $3, $4)",
+		userID, username, teamName, isActive,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+}
+
+func (f sqlFixtures) createPR(t *testing.T, prID, prName, authorID string, reviewers []string) {
+	t.Helper()
+
+	tx, err := f.db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status) VALUES ($1, $2, $3, $4)",
+		prID, prName, authorID, "OPEN",
+	)
+	if err != nil {
+		t.Fatalf("Failed to create test PR: %v", err)
+	}
+
+	for _, reviewerID := range reviewers {
+		_, err = tx.Exec(
+			"INSERT INTO pr_reviewers (pr_id, user_id) VALUES ($1, $2)",
+			prID, reviewerID,
+		)
+		if err != nil {
+			t.Fatalf("Failed to add reviewer to PR: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+}
+
+func (f sqlFixtures) ageAssignment(t *testing.T, prID, userID string, minutes int) {
+	t.Helper()
+	query := `UPDATE pr_reviewers SET assigned_at = LOCALTIMESTAMP - make_interval(mins => $1) 
+	          WHERE pr_id = $2 AND user_id = $3`
+	if f.sqlite {
+		query = `UPDATE pr_reviewers SET assigned_at = strftime('%Y-%m-%d %H:%M:%f', 'now', '-' || $1 || ' minutes') 
+		         WHERE pr_id = $2 AND user_id = $3`
+	}
+	_, err := f.db.Exec(query, minutes, prID, userID)
+	if err != nil {
+		t.Fatalf("Failed to age assignment: %v", err)
+	}
+}
+
+// memoryFixtures fills in the same column defaults as the Postgres schema.
+type memoryFixtures struct {
+	store *memory.Store
+}
+
+// clean has nothing to do: every suite gets a fresh store.
+func (f memoryFixtures) clean(t *testing.T) {}
+
+func (f memoryFixtures) createTeam(t *testing.T, teamName string) {
+	t.Helper()
+	err := f.store.CreateTeam(context.Background(), &models.Team{
+		TeamName:         teamName,
+		ReviewerStrategy: "least_loaded",
+		MaxReviewers:     2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test team: %v", err)
+	}
+}
+
+func (f memoryFixtures) createUser(t *testing.T, userID, username, teamName string, isActive bool) {
+	t.Helper()
+	err := f.store.CreateUser(context.Background(), &models.User{
+		UserID:       userID,
+		Username:     username,
+		TeamName:     teamName,
+		IsActive:     isActive,
+		ReviewWeight: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+}
+
+func (f memoryFixtures) createPR(t *testing.T, prID, prName, authorID string, reviewers []string) {
+	t.Helper()
+	err := f.store.InsertPR(context.Background(), &models.PullRequest{
+		PullRequestID:     prID,
+		PullRequestName:   prName,
+		AuthorID:          authorID,
+		Status:            models.PRStatusOpen,
+		AssignedReviewers: reviewers,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test PR: %v", err)
+	}
+}
+
+func (f memoryFixtures) ageAssignment(t *testing.T, prID, userID string, minutes int) {
+	f.store.SetAssignedAt(prID, userID, time.Now().Add(-time.Duration(minutes)*time.Minute))
+}
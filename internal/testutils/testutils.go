@@ -3,7 +3,6 @@ package testutils
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -11,7 +10,6 @@ import (
 	"time"
 
 	"github.com/lypolix/avito_test/internal/config"
-	"github.com/lypolix/avito_test/internal/database"
 	"github.com/lypolix/avito_test/internal/handlers"
 	"github.com/lypolix/avito_test/internal/repository"
 	"github.com/lypolix/avito_test/internal/server"
@@ -22,21 +20,23 @@ import (
 
 type TestSuite struct {
 	DB         *sql.DB
-	Repo       *repository.Repository
+	Repo       repository.Store
 	Service    *services.Service
 	Handler    *handlers.Handler
 	Server     *server.Server
 	Config     *config.Config
 	HTTPClient *http.Client
 	BaseURL    string
+	fixtures   fixtures
 }
 
 type IntegrationTestSuite struct {
-	DB      *sql.DB
-	Repo    *repository.Repository
-	Service *services.Service
-	Config  *config.Config
-	Ctx     context.Context
+	DB       *sql.DB
+	Repo     repository.Store
+	Service  *services.Service
+	Config   *config.Config
+	Ctx      context.Context
+	fixtures fixtures
 }
 
 func SetupTestSuite(t *testing.T) *TestSuite {
@@ -50,31 +50,31 @@ func SetupTestSuite(t *testing.T) *TestSuite {
 			IdleTimeout:  60 * time.Second,
 		},
 		Database: config.DatabaseConfig{
+			Driver:          getEnv("TEST_STORAGE", config.DriverPostgres),
 			Host:            getEnv("TEST_DB_HOST", "localhost"),
 			Port:            getEnv("TEST_DB_PORT", "55432"),
 			User:            getEnv("TEST_DB_USER", "test_user"),
 			Password:        getEnv("TEST_DB_PASSWORD", "test_pass"),
 			Name:            getEnv("TEST_DB_NAME", "appdb_test"),
+			Path:            getEnv("TEST_DB_PATH", ""),
 			MaxOpenConns:    10,
 			MaxIdleConns:    5,
 			ConnMaxLifetime: 5 * time.Minute,
 			MaxRetries:      3,
 			RetryInterval:   1 * time.Second,
+			QueryTimeout:    5 * time.Second,
 		},
 		App: config.AppConfig{
 			Env:             "test",
 			ShutdownTimeout: 5 * time.Second,
+			RequestTimeout:  5 * time.Second,
+			AdminToken:      "test-admin-token",
 		},
 	}
 
-	db, err := database.ConnectWithRetry(cfg.Database)
-	if err != nil {
-		t.Fatalf("Failed to connect to test database: %v", err)
-	}
-
-	repo := repository.NewRepository(db)
+	db, repo, fixtures := openStore(t, cfg.Database)
 	service := services.NewService(repo)
-	handler := handlers.NewHandler(service)
+	handler := handlers.NewHandler(service, cfg.App)
 
 	srv := server.New(cfg.Server)
 	srv.SetupRoutes(handler.SetupRoutesWithRouter)
@@ -101,6 +101,7 @@ func SetupTestSuite(t *testing.T) *TestSuite {
 		Config:     cfg,
 		HTTPClient: client,
 		BaseURL:    baseURL,
+		fixtures:   fixtures,
 	}
 
 	suite.CleanDatabase(t)
@@ -112,33 +113,32 @@ func SetupIntegrationTestSuite(t *testing.T) *IntegrationTestSuite {
 
 	cfg := &config.Config{
 		Database: config.DatabaseConfig{
+			Driver:          getEnv("TEST_STORAGE", config.DriverPostgres),
 			Host:            getEnv("TEST_DB_HOST", "localhost"),
 			Port:            getEnv("TEST_DB_PORT", "55432"),
 			User:            getEnv("TEST_DB_USER", "test_user"),
 			Password:        getEnv("TEST_DB_PASSWORD", "test_pass"),
 			Name:            getEnv("TEST_DB_NAME", "appdb_test"),
+			Path:            getEnv("TEST_DB_PATH", ""),
 			MaxOpenConns:    10,
 			MaxIdleConns:    5,
 			ConnMaxLifetime: 5 * time.Minute,
 			MaxRetries:      3,
 			RetryInterval:   1 * time.Second,
+			QueryTimeout:    5 * time.Second,
 		},
 	}
 
-	db, err := database.ConnectWithRetry(cfg.Database)
-	if err != nil {
-		t.Fatalf("Failed to connect to test database: %v", err)
-	}
-
-	repo := repository.NewRepository(db)
+	db, repo, fixtures := openStore(t, cfg.Database)
 	service := services.NewService(repo)
 
 	suite := &IntegrationTestSuite{
-		DB:      db,
-		Repo:    repo,
-		Service: service,
-		Config:  cfg,
-		Ctx:     context.Background(),
+		DB:       db,
+		Repo:     repo,
+		Service:  service,
+		Config:   cfg,
+		Ctx:      context.Background(),
+		fixtures: fixtures,
 	}
 
 	suite.CleanDatabase(t)
@@ -146,22 +146,11 @@ func SetupIntegrationTestSuite(t *testing.T) *IntegrationTestSuite {
 }
 
 func (ts *TestSuite) CleanDatabase(t *testing.T) {
-	cleanDatabase(t, ts.DB)
+	ts.fixtures.clean(t)
 }
 
 func (its *IntegrationTestSuite) CleanDatabase(t *testing.T) {
-	cleanDatabase(t, its.DB)
-}
-
-func cleanDatabase(t *testing.T, db *sql.DB) {
-	t.Helper()
-	tables := []string{"pr_reviewers", "pull_requests", "users", "teams"}
-	for _, table := range tables {
-		_, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table))
-		if err != nil {
-			t.Fatalf("Failed to clean table %s: %v", table, err)
-		}
-	}
+	its.fixtures.clean(t)
 }
 
 func (ts *TestSuite) TearDown(t *testing.T) {
@@ -174,92 +163,51 @@ func (ts *TestSuite) TearDown(t *testing.T) {
 		t.Logf("Error shutting down test server: %v", err)
 	}
 
-	if err := ts.DB.Close(); err != nil {
-		t.Logf("Error closing database connection: %v", err)
-	}
+	closeDB(t, ts.DB)
 }
 
 func (its *IntegrationTestSuite) TearDown(t *testing.T) {
 	t.Helper()
+	closeDB(t, its.DB)
+}
 
-	if err := its.DB.Close(); err != nil {
+func closeDB(t *testing.T, db *sql.DB) {
+	t.Helper()
+	if db == nil {
+		return
+	}
+	if err := db.Close(); err != nil {
 		t.Logf("Error closing database connection: %v", err)
 	}
 }
 
 func (ts *TestSuite) CreateTestTeam(t *testing.T, teamName string) {
-	createTestTeam(t, ts.DB, teamName)
+	ts.fixtures.createTeam(t, teamName)
 }
 
 func (its *IntegrationTestSuite) CreateTestTeam(t *testing.T, teamName string) {
-	createTestTeam(t, its.DB, teamName)
-}
-
-func createTestTeam(t *testing.T, db *sql.DB, teamName string) {
-	t.Helper()
-	_, err := db.Exec("INSERT INTO teams (team_name) VALUES ($1)", teamName)
-	if err != nil {
-		t.Fatalf("Failed to create test team: %v", err)
-	}
+	its.fixtures.createTeam(t, teamName)
 }
 
 func (ts *TestSuite) CreateTestUser(t *testing.T, userID, username, teamName string, isActive bool) {
-	createTestUser(t, ts.DB, userID, username, teamName, isActive)
+	ts.fixtures.createUser(t, userID, username, teamName, isActive)
 }
 
 func (its *IntegrationTestSuite) CreateTestUser(t *testing.T, userID, username, teamName string, isActive bool) {
-	createTestUser(t, its.DB, userID, username, teamName, isActive)
-}
-
-func createTestUser(t *testing.T, db *sql.DB, userID, username, teamName string, isActive bool) {
-	t.Helper()
-	_, err := db.Exec(
-		"INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, $3, $4)",
-		userID, username, teamName, isActive,
-	)
-	if err != nil {
-		t.Fatalf("Failed to create test user: %v", err)
-	}
+	its.fixtures.createUser(t, userID, username, teamName, isActive)
 }
 
 func (ts *TestSuite) CreateTestPR(t *testing.T, prID, prName, authorID string, reviewers []string) {
-	createTestPR(t, ts.DB, prID, prName, authorID, reviewers)
+	ts.fixtures.createPR(t, prID, prName, authorID, reviewers)
 }
 
 func (its *IntegrationTestSuite) CreateTestPR(t *testing.T, prID, prName, authorID string, reviewers []string) {
-	createTestPR(t, its.DB, prID, prName, authorID, reviewers)
+	its.fixtures.createPR(t, prID, prName, authorID, reviewers)
 }
 
-func createTestPR(t *testing.T, db *sql.DB, prID, prName, authorID string, reviewers []string) {
-	t.Helper()
-
-	tx, err := db.Begin()
-	if err != nil {
-		t.Fatalf("Failed to begin transaction: %v", err)
-	}
-	defer tx.Rollback()
-
-	_, err = tx.Exec(
-		"INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status) VALUES ($1, $2, $3, $4)",
-		prID, prName, authorID, "OPEN",
-	)
-	if err != nil {
-		t.Fatalf("Failed to create test PR: %v", err)
-	}
-
-	for _, reviewerID := range reviewers {
-		_, err = tx.Exec(
-			"INSERT INTO pr_reviewers (pr_id, user_id) VALUES ($1, $2)",
-			prID, reviewerID,
-		)
-		if err != nil {
-			t.Fatalf("Failed to add reviewer to PR: %v", err)
-		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		t.Fatalf("Failed to commit transaction: %v", err)
-	}
+// AgeAssignment moves the assignment of the reviewer the given number of minutes into the past.
+func (its *IntegrationTestSuite) AgeAssignment(t *testing.T, prID, userID string, minutes int) {
+	its.fixtures.ageAssignment(t, prID, userID, minutes)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+// Sink turns outbox events into pending deliveries for every subscribed webhook of the team.
+// A retried event does not create a second delivery for the same webhook.
+type Sink struct {
+	repo repository.WebhookStore
+}
+
+func NewSink(repo repository.WebhookStore) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) Name() string {
+	return "webhooks"
+}
+
+func (s *Sink) Publish(ctx context.Context, event models.Event) error {
+	webhooks, err := s.repo.GetActiveWebhooksByTeam(ctx, event.TeamName)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+
+		delivery := &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: time.Now().UTC(),
+			OutboxEventID: &event.ID,
+		}
+		if _, err := s.repo.CreateWebhookDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lypolix/avito_test/internal/config"
+	"github.com/lypolix/avito_test/internal/models"
+	"github.com/lypolix/avito_test/internal/repository"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Worker sends pending webhook deliveries and retries failed ones with exponential backoff.
+type Worker struct {
+	repo   repository.WebhookStore
+	cfg    config.WebhookConfig
+	client *http.Client
+}
+
+func NewWorker(repo repository.WebhookStore, cfg config.WebhookConfig) *Worker {
+	return &Worker{
+		repo:   repo,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.ProcessDue(ctx); err != nil {
+				slog.ErrorContext(ctx, "webhook delivery failed", "error", err)
+			}
+		}
+	}
+}
+
+// ProcessDue sends every delivery whose next attempt is due and returns how many were attempted.
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	deliveries, err := w.repo.GetDueWebhookDeliveries(ctx, now, w.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		claimed, err := w.repo.ClaimWebhookDelivery(ctx, delivery.ID, now, now.Add(w.cfg.Timeout*2))
+		if err != nil {
+			return attempted, err
+		}
+		if !claimed {
+			continue
+		}
+
+		webhook, err := w.repo.GetWebhook(ctx, delivery.WebhookID)
+		if err != nil {
+			return attempted, err
+		}
+		if webhook == nil {
+			continue
+		}
+
+		w.attempt(ctx, webhook, delivery)
+		attempted++
+
+		if err := w.repo.UpdateWebhookDeliveryResult(ctx, delivery); err != nil {
+			return attempted, err
+		}
+	}
+
+	return attempted, nil
+}
+
+func (w *Worker) attempt(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+
+	code, err := w.send(ctx, webhook, delivery)
+	now := time.Now().UTC()
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= w.cfg.MaxAttempts {
+		delivery.Status = models.DeliveryFailed
+		return
+	}
+	delivery.NextAttemptAt = now.Add(Backoff(w.cfg, delivery.Attempts))
+}
+
+func (w *Worker) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, delivery.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header: "sha256=" followed by the hex HMAC of the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff doubles the delay after every failed attempt, starting from BaseBackoff and capped at MaxBackoff.
+func Backoff(cfg config.WebhookConfig, attempts int) time.Duration {
+	delay := cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,3 @@
+ALTER TABLE teams ADD COLUMN reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random';
+
+ALTER TABLE users ADD COLUMN review_weight INTEGER NOT NULL DEFAULT 1 CHECK (review_weight > 0);
@@ -0,0 +1,3 @@
+ALTER TABLE teams ALTER COLUMN reviewer_strategy SET DEFAULT 'random';
+
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN max_open_reviews INTEGER NULL CHECK (max_open_reviews >= 0);
+
+ALTER TABLE teams ALTER COLUMN reviewer_strategy SET DEFAULT 'least_loaded';
@@ -0,0 +1,5 @@
+ALTER TABLE teams ADD COLUMN min_reviewers INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN max_reviewers INTEGER NOT NULL DEFAULT 2;
+
+ALTER TABLE teams ADD CONSTRAINT teams_reviewers_range_check
+    CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND min_reviewers <= max_reviewers);
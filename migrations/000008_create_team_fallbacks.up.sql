@@ -0,0 +1,11 @@
+CREATE TABLE team_fallbacks (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    fallback_team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    CHECK (team_name <> fallback_team_name)
+);
+
+CREATE INDEX idx_team_fallbacks_order ON team_fallbacks(team_name, position);
+
+ALTER TABLE pr_reviewers ADD COLUMN fallback_team VARCHAR(255) NULL;
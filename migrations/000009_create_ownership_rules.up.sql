@@ -0,0 +1,16 @@
+CREATE TABLE ownership_rules (
+    id SERIAL PRIMARY KEY,
+    position INTEGER NOT NULL,
+    pattern VARCHAR(1000) NOT NULL,
+    owner_user_id VARCHAR(50) NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    owner_team_name VARCHAR(255) NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    CHECK (owner_user_id IS NULL OR owner_team_name IS NULL)
+);
+
+CREATE INDEX idx_ownership_rules_position ON ownership_rules(position);
+
+CREATE TABLE pr_files (
+    pr_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    path VARCHAR(1000) NOT NULL,
+    PRIMARY KEY (pr_id, path)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE pr_reviews (
+    id SERIAL PRIMARY KEY,
+    pr_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id),
+    state VARCHAR(20) NOT NULL CHECK (state IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    comment TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pr_reviews_pr_user ON pr_reviews(pr_id, user_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS merge_overrides;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS require_owner_approval;
+ALTER TABLE teams DROP COLUMN IF EXISTS block_on_changes_requested;
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
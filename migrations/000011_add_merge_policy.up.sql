@@ -0,0 +1,15 @@
+ALTER TABLE teams ADD COLUMN required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+ALTER TABLE teams ADD COLUMN block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD COLUMN require_owner_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE merge_overrides (
+    id SERIAL PRIMARY KEY,
+    pr_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    actor VARCHAR(255) NOT NULL,
+    requested_by VARCHAR(255) NULL,
+    reason TEXT NULL,
+    unmet_rules TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_merge_overrides_pr ON merge_overrides(pr_id);
@@ -0,0 +1,14 @@
+CREATE TABLE assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('ASSIGNED', 'REPLACED', 'REMOVED')),
+    user_id VARCHAR(50) NOT NULL,
+    previous_user_id VARCHAR(50) NULL,
+    operation VARCHAR(50) NOT NULL,
+    reason TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pr_id);
+CREATE INDEX idx_assignment_events_user ON assignment_events(user_id);
+CREATE INDEX idx_assignment_events_previous_user ON assignment_events(previous_user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_outbox_event;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS outbox_event_id;
+DROP TABLE IF EXISTS outbox_events;
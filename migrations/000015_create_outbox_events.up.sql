@@ -0,0 +1,19 @@
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    dead_lettered_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, id)
+    WHERE published_at IS NULL AND dead_lettered_at IS NULL;
+
+ALTER TABLE webhook_deliveries ADD COLUMN outbox_event_id BIGINT NULL REFERENCES outbox_events(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_outbox_event ON webhook_deliveries(webhook_id, outbox_event_id);
@@ -0,0 +1,8 @@
+ALTER TABLE assignment_events ALTER COLUMN pr_id TYPE VARCHAR(50);
+ALTER TABLE merge_overrides ALTER COLUMN pr_id TYPE VARCHAR(50);
+ALTER TABLE pr_reviews ALTER COLUMN pr_id TYPE VARCHAR(50);
+ALTER TABLE pr_files ALTER COLUMN pr_id TYPE VARCHAR(50);
+ALTER TABLE pr_reviewers ALTER COLUMN pr_id TYPE VARCHAR(50);
+ALTER TABLE pull_requests ALTER COLUMN pull_request_id TYPE VARCHAR(50);
+
+DROP TABLE IF EXISTS external_accounts;
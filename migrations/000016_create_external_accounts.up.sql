@@ -0,0 +1,16 @@
+CREATE TABLE external_accounts (
+    provider VARCHAR(20) NOT NULL,
+    login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, login)
+);
+
+CREATE INDEX idx_external_accounts_user ON external_accounts(user_id);
+
+ALTER TABLE pull_requests ALTER COLUMN pull_request_id TYPE VARCHAR(255);
+ALTER TABLE pr_reviewers ALTER COLUMN pr_id TYPE VARCHAR(255);
+ALTER TABLE pr_files ALTER COLUMN pr_id TYPE VARCHAR(255);
+ALTER TABLE pr_reviews ALTER COLUMN pr_id TYPE VARCHAR(255);
+ALTER TABLE merge_overrides ALTER COLUMN pr_id TYPE VARCHAR(255);
+ALTER TABLE assignment_events ALTER COLUMN pr_id TYPE VARCHAR(255);
@@ -0,0 +1,5 @@
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS escalation_attempted_at;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS reminded_at;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS review_escalation_minutes;
+ALTER TABLE teams DROP COLUMN IF EXISTS review_sla_minutes;
@@ -0,0 +1,5 @@
+ALTER TABLE teams ADD COLUMN review_sla_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_minutes >= 0);
+ALTER TABLE teams ADD COLUMN review_escalation_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_escalation_minutes >= 0);
+
+ALTER TABLE pr_reviewers ADD COLUMN reminded_at TIMESTAMP NULL;
+ALTER TABLE pr_reviewers ADD COLUMN escalation_attempted_at TIMESTAMP NULL;
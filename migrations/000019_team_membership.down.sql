@@ -0,0 +1,22 @@
+ALTER TABLE webhooks DROP CONSTRAINT IF EXISTS webhooks_team_name_fkey;
+ALTER TABLE webhooks ADD CONSTRAINT webhooks_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE ownership_rules DROP CONSTRAINT IF EXISTS ownership_rules_owner_team_name_fkey;
+ALTER TABLE ownership_rules ADD CONSTRAINT ownership_rules_owner_team_name_fkey
+    FOREIGN KEY (owner_team_name) REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE team_fallbacks DROP CONSTRAINT IF EXISTS team_fallbacks_fallback_team_name_fkey;
+ALTER TABLE team_fallbacks ADD CONSTRAINT team_fallbacks_fallback_team_name_fkey
+    FOREIGN KEY (fallback_team_name) REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE team_fallbacks DROP CONSTRAINT IF EXISTS team_fallbacks_team_name_fkey;
+ALTER TABLE team_fallbacks ADD CONSTRAINT team_fallbacks_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name);
+
+-- Fails while users removed from their team still exist.
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
@@ -0,0 +1,21 @@
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON UPDATE CASCADE;
+
+ALTER TABLE team_fallbacks DROP CONSTRAINT IF EXISTS team_fallbacks_team_name_fkey;
+ALTER TABLE team_fallbacks ADD CONSTRAINT team_fallbacks_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE team_fallbacks DROP CONSTRAINT IF EXISTS team_fallbacks_fallback_team_name_fkey;
+ALTER TABLE team_fallbacks ADD CONSTRAINT team_fallbacks_fallback_team_name_fkey
+    FOREIGN KEY (fallback_team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE ownership_rules DROP CONSTRAINT IF EXISTS ownership_rules_owner_team_name_fkey;
+ALTER TABLE ownership_rules ADD CONSTRAINT ownership_rules_owner_team_name_fkey
+    FOREIGN KEY (owner_team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE webhooks DROP CONSTRAINT IF EXISTS webhooks_team_name_fkey;
+ALTER TABLE webhooks ADD CONSTRAINT webhooks_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
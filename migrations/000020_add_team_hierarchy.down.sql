@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_teams_parent;
+
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_parent_not_self;
+ALTER TABLE teams DROP COLUMN IF EXISTS inherit_policy;
+ALTER TABLE teams DROP COLUMN IF EXISTS parent_team;
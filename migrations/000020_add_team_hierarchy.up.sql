@@ -0,0 +1,5 @@
+ALTER TABLE teams ADD COLUMN parent_team VARCHAR(255) NULL REFERENCES teams(team_name) ON UPDATE CASCADE;
+ALTER TABLE teams ADD COLUMN inherit_policy BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD CONSTRAINT teams_parent_not_self CHECK (parent_team <> team_name);
+
+CREATE INDEX idx_teams_parent ON teams(parent_team);
@@ -0,0 +1,8 @@
+// Package migrations holds the Postgres schema. The files are built into the server, which
+// applies them with `server migrate up` or on startup when DB_AUTO_MIGRATE is set.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS user_absences;
+DROP TABLE IF EXISTS external_accounts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS assignment_events;
+DROP TABLE IF EXISTS merge_overrides;
+DROP TABLE IF EXISTS pr_reviews;
+DROP TABLE IF EXISTS pr_files;
+DROP TABLE IF EXISTS ownership_rules;
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,199 @@
+-- The schema of migrations/000001..000023 in SQLite form. Timestamps are UTC text
+-- with fractional seconds, which is what the driver writes and what compares in order.
+
+CREATE TABLE teams (
+    team_name VARCHAR(255) PRIMARY KEY,
+    parent_team VARCHAR(255) NULL REFERENCES teams(team_name) ON UPDATE CASCADE,
+    inherit_policy BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'least_loaded',
+    min_reviewers INTEGER NOT NULL DEFAULT 0,
+    max_reviewers INTEGER NOT NULL DEFAULT 2,
+    required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals >= 0),
+    block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    require_owner_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    review_sla_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_minutes >= 0),
+    review_escalation_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_escalation_minutes >= 0),
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT teams_reviewers_range_check
+        CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND min_reviewers <= max_reviewers),
+    CONSTRAINT teams_parent_not_self CHECK (parent_team <> team_name)
+);
+
+CREATE INDEX idx_teams_parent ON teams(parent_team);
+
+CREATE TABLE users (
+    user_id VARCHAR(50) PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NULL REFERENCES teams(team_name) ON UPDATE CASCADE,
+    is_active BOOLEAN DEFAULT TRUE,
+    review_weight INTEGER NOT NULL DEFAULT 1 CHECK (review_weight > 0),
+    max_open_reviews INTEGER NULL CHECK (max_open_reviews >= 0),
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX idx_users_team_name ON users(team_name);
+CREATE INDEX idx_users_active ON users(is_active) WHERE is_active = TRUE;
+CREATE INDEX idx_users_team_active ON users(team_name, is_active);
+
+CREATE TABLE pull_requests (
+    pull_request_id VARCHAR(255) PRIMARY KEY,
+    pull_request_name VARCHAR(500) NOT NULL,
+    author_id VARCHAR(50) NOT NULL REFERENCES users(user_id),
+    status VARCHAR(20) DEFAULT 'OPEN'
+        CONSTRAINT pull_requests_status_check CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    merged_at TIMESTAMP NULL,
+    closed_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_pr_author ON pull_requests(author_id);
+CREATE INDEX idx_pr_status ON pull_requests(status);
+
+CREATE TABLE pr_reviewers (
+    pr_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id),
+    assigned_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    reminded_at TIMESTAMP NULL,
+    escalation_attempted_at TIMESTAMP NULL,
+    fallback_team VARCHAR(255) NULL,
+    PRIMARY KEY (pr_id, user_id)
+);
+
+CREATE INDEX idx_reviewer_user ON pr_reviewers(user_id);
+
+CREATE TABLE team_fallbacks (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    fallback_team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    CHECK (team_name <> fallback_team_name)
+);
+
+CREATE INDEX idx_team_fallbacks_order ON team_fallbacks(team_name, position);
+
+CREATE TABLE ownership_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    position INTEGER NOT NULL,
+    pattern VARCHAR(1000) NOT NULL,
+    owner_user_id VARCHAR(50) NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    owner_team_name VARCHAR(255) NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    CHECK (owner_user_id IS NULL OR owner_team_name IS NULL)
+);
+
+CREATE INDEX idx_ownership_rules_position ON ownership_rules(position);
+
+CREATE TABLE pr_files (
+    pr_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    path VARCHAR(1000) NOT NULL,
+    PRIMARY KEY (pr_id, path)
+);
+
+CREATE TABLE pr_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id),
+    state VARCHAR(20) NOT NULL CHECK (state IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    comment TEXT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX idx_pr_reviews_pr_user ON pr_reviews(pr_id, user_id);
+
+CREATE TABLE merge_overrides (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    actor VARCHAR(255) NOT NULL,
+    requested_by VARCHAR(255) NULL,
+    reason TEXT NULL,
+    unmet_rules TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX idx_merge_overrides_pr ON merge_overrides(pr_id);
+
+CREATE TABLE assignment_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('ASSIGNED', 'REPLACED', 'REMOVED')),
+    user_id VARCHAR(50) NOT NULL,
+    previous_user_id VARCHAR(50) NULL,
+    operation VARCHAR(50) NOT NULL,
+    reason TEXT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pr_id);
+CREATE INDEX idx_assignment_events_user ON assignment_events(user_id);
+CREATE INDEX idx_assignment_events_previous_user ON assignment_events(previous_user_id);
+
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    event_types TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX idx_webhooks_team ON webhooks(team_name);
+
+CREATE TABLE outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type VARCHAR(50) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    dead_lettered_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, id)
+    WHERE published_at IS NULL AND dead_lettered_at IS NULL;
+
+CREATE TABLE webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER NULL,
+    last_error TEXT NULL,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    replay_of BIGINT NULL REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    outbox_event_id BIGINT NULL REFERENCES outbox_events(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    delivered_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+CREATE UNIQUE INDEX idx_webhook_deliveries_outbox_event ON webhook_deliveries(webhook_id, outbox_event_id);
+
+CREATE TABLE external_accounts (
+    provider VARCHAR(20) NOT NULL,
+    login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    PRIMARY KEY (provider, login)
+);
+
+CREATE INDEX idx_external_accounts_user ON external_accounts(user_id);
+
+CREATE TABLE user_absences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason VARCHAR(255) NULL,
+    reassigned_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_user_absences_user ON user_absences(user_id, ends_at);
+CREATE INDEX idx_user_absences_pending ON user_absences(starts_at) WHERE reassigned_at IS NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    endpoint VARCHAR(100) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER NULL,
+    response_body BLOB NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    PRIMARY KEY (idempotency_key, endpoint)
+);
+
+CREATE INDEX idx_idempotency_keys_expires ON idempotency_keys(expires_at);
@@ -0,0 +1,8 @@
+// Package sqlite holds the schema of the SQLite backend. It is equivalent to the Postgres
+// migrations one directory up and is built into the server, which applies it on startup.
+package sqlite
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS